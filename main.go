@@ -3,12 +3,19 @@ package main
 import (
 	"context"
 
+	"github.com/admiralbulldogtv/yappercontroller/src/blacklist"
+	"github.com/admiralbulldogtv/yappercontroller/src/cache"
+	"github.com/admiralbulldogtv/yappercontroller/src/channels"
 	"github.com/admiralbulldogtv/yappercontroller/src/configure"
+	"github.com/admiralbulldogtv/yappercontroller/src/deadletters"
 	"github.com/admiralbulldogtv/yappercontroller/src/global"
 	"github.com/admiralbulldogtv/yappercontroller/src/manager"
 	"github.com/admiralbulldogtv/yappercontroller/src/mongo"
+	"github.com/admiralbulldogtv/yappercontroller/src/pronunciations"
+	"github.com/admiralbulldogtv/yappercontroller/src/queue"
 	"github.com/admiralbulldogtv/yappercontroller/src/redis"
 	"github.com/admiralbulldogtv/yappercontroller/src/tts"
+	"github.com/admiralbulldogtv/yappercontroller/src/workers"
 	"github.com/sirupsen/logrus"
 )
 
@@ -33,15 +40,65 @@ func main() {
 		logrus.WithError(err).Fatal("failed to start redis")
 	}
 
-	ttsInst, err := tts.NewInstance(ctx, ctx.Config().Redis.TaskSetKey, ctx.Config().Redis.OutputEvent)
+	ctx.Inst().Mongo = mongoInst
+	ctx.Inst().Redis = redisInst
+
+	cacheInst, err := cache.NewInstance(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to start cache")
+	}
+
+	ctx.Inst().Cache = cacheInst
+
+	workersInst, err := workers.NewInstance(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to start worker registry")
+	}
+
+	ctx.Inst().Workers = workersInst
+
+	ttsInst, err := tts.NewInstance(ctx)
 	if err != nil {
 		logrus.WithError(err).Fatal("failed to start tts")
 	}
 
-	ctx.Inst().Mongo = mongoInst
-	ctx.Inst().Redis = redisInst
 	ctx.Inst().TTS = ttsInst
 
+	channelsInst, err := channels.NewInstance(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load channels")
+	}
+
+	ctx.Inst().Channels = channelsInst
+
+	blacklistInst, err := blacklist.NewInstance(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load blacklist")
+	}
+
+	ctx.Inst().Blacklist = blacklistInst
+
+	pronunciationsInst, err := pronunciations.NewInstance(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load pronunciations")
+	}
+
+	ctx.Inst().Pronunciations = pronunciationsInst
+
+	deadLettersInst, err := deadletters.NewInstance(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to start dead letters")
+	}
+
+	ctx.Inst().DeadLetters = deadLettersInst
+
+	queueInst, err := queue.NewInstance(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to start queue")
+	}
+
+	ctx.Inst().Queue = queueInst
+
 	done := manager.New(ctx)
 
 	<-done
@@ -65,12 +65,15 @@ var (
 		"Cheer10000.wav":   44,
 		"Cheer100000.wav":  100,
 	}
+	// Sounds are the sound bytes which can be used in messages, keyed by their name without the extension.
+	Sounds = map[string]Alert{}
 )
 
 func init() {
 	cheerBox := packr.New("cheer-alerts", "./DonationAlerts/Cheer")
 	donationBox := packr.New("donation-alerts", "./DonationAlerts/Donation")
 	subscriberBox := packr.New("subscriber-alerts", "./DonationAlerts/Subscriber")
+	soundBox := packr.New("sound-bytes", "./Sounds")
 
 	h := sha256.New()
 	for _, v := range cheerBox.List() {
@@ -111,4 +114,19 @@ func init() {
 			Volume:   VolumeSettings[v],
 		}
 	}
+
+	for _, v := range soundBox.List() {
+		if !strings.HasSuffix(v, ".wav") {
+			continue
+		}
+		data, _ := soundBox.Find(v)
+		h.Reset()
+		_, _ = h.Write(data)
+
+		Sounds[strings.TrimSuffix(v, ".wav")] = Alert{
+			Name:     v,
+			Data:     data,
+			CheckSum: hex.EncodeToString(h.Sum(nil))[:8],
+		}
+	}
 }
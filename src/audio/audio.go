@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"math"
+	"time"
+)
+
+// Format is the layout of pcm audio.
+type Format struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+}
+
+// Buffer is decoded audio, the samples of every channel are interleaved and between -1 and 1.
+type Buffer struct {
+	SampleRate int
+	Channels   int
+	Samples    []float64
+}
+
+// Frames is the number of samples per channel.
+func (b *Buffer) Frames() int {
+	if b.Channels == 0 {
+		return 0
+	}
+	return len(b.Samples) / b.Channels
+}
+
+func (b *Buffer) Duration() time.Duration {
+	if b.SampleRate == 0 {
+		return 0
+	}
+	return time.Duration(float64(b.Frames()) / float64(b.SampleRate) * float64(time.Second))
+}
+
+// Silence creates d of silence in the sample rate and channels of f.
+func Silence(f Format, d time.Duration) *Buffer {
+	frames := int(math.Round(d.Seconds() * float64(f.SampleRate)))
+	return &Buffer{
+		SampleRate: f.SampleRate,
+		Channels:   f.Channels,
+		Samples:    make([]float64, frames*f.Channels),
+	}
+}
+
+// Convert returns the buffer in the sample rate and channels of f, the buffer itself is returned when it already matches.
+func (b *Buffer) Convert(f Format) *Buffer {
+	out := b
+	if out.Channels != f.Channels {
+		out = remix(out, f.Channels)
+	}
+	if out.SampleRate != f.SampleRate {
+		out = resample(out, f.SampleRate)
+	}
+	return out
+}
+
+// Concat converts every buffer to f and joins them.
+func Concat(f Format, bufs ...*Buffer) *Buffer {
+	out := &Buffer{
+		SampleRate: f.SampleRate,
+		Channels:   f.Channels,
+	}
+
+	size := 0
+	converted := make([]*Buffer, len(bufs))
+	for i, b := range bufs {
+		converted[i] = b.Convert(f)
+		size += len(converted[i].Samples)
+	}
+
+	out.Samples = make([]float64, 0, size)
+	for _, b := range converted {
+		out.Samples = append(out.Samples, b.Samples...)
+	}
+
+	return out
+}
+
+// remix mixes down to mono by averaging, mono is copied to every channel
+// and other layouts reuse the source channels in order.
+func remix(b *Buffer, channels int) *Buffer {
+	frames := b.Frames()
+	out := &Buffer{
+		SampleRate: b.SampleRate,
+		Channels:   channels,
+		Samples:    make([]float64, frames*channels),
+	}
+
+	for i := 0; i < frames; i++ {
+		frame := b.Samples[i*b.Channels : (i+1)*b.Channels]
+		if channels == 1 {
+			sum := 0.0
+			for _, s := range frame {
+				sum += s
+			}
+			out.Samples[i] = sum / float64(len(frame))
+			continue
+		}
+		for c := 0; c < channels; c++ {
+			out.Samples[i*channels+c] = frame[c%len(frame)]
+		}
+	}
+
+	return out
+}
+
+// resample changes the sample rate with linear interpolation.
+func resample(b *Buffer, sampleRate int) *Buffer {
+	in := b.Frames()
+	frames := int(math.Round(float64(in) * float64(sampleRate) / float64(b.SampleRate)))
+	out := &Buffer{
+		SampleRate: sampleRate,
+		Channels:   b.Channels,
+		Samples:    make([]float64, frames*b.Channels),
+	}
+	if in == 0 {
+		return out
+	}
+
+	step := float64(b.SampleRate) / float64(sampleRate)
+	for i := 0; i < frames; i++ {
+		pos := float64(i) * step
+		j := int(pos)
+		frac := pos - float64(j)
+		if j >= in-1 {
+			j, frac = in-1, 0
+		}
+		for c := 0; c < b.Channels; c++ {
+			s := b.Samples[j*b.Channels+c]
+			if frac != 0 {
+				s += (b.Samples[(j+1)*b.Channels+c] - s) * frac
+			}
+			out.Samples[i*b.Channels+c] = s
+		}
+	}
+
+	return out
+}
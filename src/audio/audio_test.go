@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+var outputFormat = Format{SampleRate: 22050, Channels: 1, BitsPerSample: 16}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format Format
+		want   string
+	}{
+		{"stereo to mono and upsample", "stereo8.wav", outputFormat, "stereo8_mono16_22050.wav"},
+		{"downsample", "mono24.wav", outputFormat, "mono24_mono16_22050.wav"},
+		{"mono to stereo and upsample", "mono16.wav", Format{44100, 2, 16}, "mono16_stereo16_44100.wav"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := decode(t, tt.input).Convert(tt.format)
+			if b.SampleRate != tt.format.SampleRate || b.Channels != tt.format.Channels {
+				t.Fatalf("got %d Hz with %d channels", b.SampleRate, b.Channels)
+			}
+			if got := encode(t, b, tt.format); !bytes.Equal(got, fixture(t, tt.want)) {
+				t.Fatalf("output differs from %s", tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertMatching(t *testing.T) {
+	b := decode(t, "mono16.wav")
+	if b.Convert(outputFormat) != b {
+		t.Fatal("a matching buffer was copied")
+	}
+}
+
+func TestSilence(t *testing.T) {
+	b := Silence(Format{44100, 2, 16}, 10*time.Millisecond)
+	if b.Frames() != 441 || len(b.Samples) != 882 {
+		t.Fatalf("got %d frames and %d samples", b.Frames(), len(b.Samples))
+	}
+	if b.Duration() != 10*time.Millisecond {
+		t.Fatalf("got a duration of %s", b.Duration())
+	}
+	for _, s := range b.Samples {
+		if s != 0 {
+			t.Fatal("silence is not silent")
+		}
+	}
+}
+
+func TestConcat(t *testing.T) {
+	b := Concat(outputFormat, decode(t, "mono16.wav"), Silence(outputFormat, 10*time.Millisecond), decode(t, "stereo8.wav"))
+	if b.Frames() != 16+221+32 {
+		t.Fatalf("got %d frames", b.Frames())
+	}
+	if got := encode(t, b, outputFormat); !bytes.Equal(got, fixture(t, "concat.wav")) {
+		t.Fatal("output differs from concat.wav")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	// 16 frames at 44100 Hz.
+	if d := decode(t, "mono24.wav").Duration(); d != 362811*time.Nanosecond {
+		t.Fatalf("got a duration of %s", d)
+	}
+	if d := (&Buffer{}).Duration(); d != 0 {
+		t.Fatalf("an empty buffer lasts %s", d)
+	}
+}
@@ -0,0 +1,173 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const (
+	formatPCM        = 1
+	formatFloat      = 3
+	formatExtensible = 0xFFFE
+)
+
+var (
+	ErrInvalid     = fmt.Errorf("invalid wav")
+	ErrUnsupported = fmt.Errorf("unsupported wav")
+)
+
+// Decode reads a pcm or float wav, including WAVE_FORMAT_EXTENSIBLE files.
+func Decode(data []byte) (*Buffer, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, ErrInvalid
+	}
+
+	var (
+		format   uint16
+		f        Format
+		hasFmt   bool
+		samples  []byte
+		hasData  bool
+		position = 12
+	)
+	for position+8 <= len(data) {
+		id := string(data[position : position+4])
+		size := int(binary.LittleEndian.Uint32(data[position+4 : position+8]))
+		chunk := data[position+8:]
+		// writers which stream the output do not know the size of the data.
+		if size > len(chunk) || size < 0 {
+			size = len(chunk)
+		}
+		chunk = chunk[:size]
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, ErrInvalid
+			}
+			format = binary.LittleEndian.Uint16(chunk[0:2])
+			f.Channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+			f.SampleRate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			f.BitsPerSample = int(binary.LittleEndian.Uint16(chunk[14:16]))
+			if format == formatExtensible {
+				if size < 40 {
+					return nil, ErrInvalid
+				}
+				// the sub format guid starts with the actual format.
+				format = binary.LittleEndian.Uint16(chunk[24:26])
+			}
+			hasFmt = true
+		case "data":
+			samples = chunk
+			hasData = true
+		}
+
+		position += 8 + size + size%2
+	}
+
+	if !hasFmt || !hasData || f.Channels == 0 || f.SampleRate == 0 {
+		return nil, ErrInvalid
+	}
+
+	read, err := reader(format, f.BitsPerSample)
+	if err != nil {
+		return nil, err
+	}
+
+	width := f.BitsPerSample / 8
+	frames := len(samples) / (width * f.Channels)
+	b := &Buffer{
+		SampleRate: f.SampleRate,
+		Channels:   f.Channels,
+		Samples:    make([]float64, frames*f.Channels),
+	}
+	for i := range b.Samples {
+		b.Samples[i] = read(samples[i*width : (i+1)*width])
+	}
+
+	return b, nil
+}
+
+func reader(format uint16, bits int) (func([]byte) float64, error) {
+	switch {
+	case format == formatPCM && bits == 8:
+		return func(b []byte) float64 {
+			return float64(int(b[0])-128) / 128
+		}, nil
+	case format == formatPCM && bits == 16:
+		return func(b []byte) float64 {
+			return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+		}, nil
+	case format == formatPCM && bits == 24:
+		return func(b []byte) float64 {
+			return float64(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24)>>8) / (1 << 23)
+		}, nil
+	case format == formatPCM && bits == 32:
+		return func(b []byte) float64 {
+			return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+		}, nil
+	case format == formatFloat && bits == 32:
+		return func(b []byte) float64 {
+			return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		}, nil
+	case format == formatFloat && bits == 64:
+		return func(b []byte) float64 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: format %d with %d bits", ErrUnsupported, format, bits)
+}
+
+// Encode converts the buffer to f and writes it as a pcm wav.
+func Encode(b *Buffer, f Format) ([]byte, error) {
+	switch f.BitsPerSample {
+	case 8, 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("%w: %d bits", ErrUnsupported, f.BitsPerSample)
+	}
+
+	b = b.Convert(f)
+	width := f.BitsPerSample / 8
+	size := len(b.Samples) * width
+
+	data := make([]byte, 44+size)
+	copy(data[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(data[4:8], uint32(36+size))
+	copy(data[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:20], 16)
+	binary.LittleEndian.PutUint16(data[20:22], formatPCM)
+	binary.LittleEndian.PutUint16(data[22:24], uint16(f.Channels))
+	binary.LittleEndian.PutUint32(data[24:28], uint32(f.SampleRate))
+	binary.LittleEndian.PutUint32(data[28:32], uint32(f.SampleRate*f.Channels*width))
+	binary.LittleEndian.PutUint16(data[32:34], uint16(f.Channels*width))
+	binary.LittleEndian.PutUint16(data[34:36], uint16(f.BitsPerSample))
+	copy(data[36:40], "data")
+	binary.LittleEndian.PutUint32(data[40:44], uint32(size))
+
+	scale := float64(int64(1) << (f.BitsPerSample - 1))
+	out := data[44:]
+	for i, s := range b.Samples {
+		v := int64(math.Round(s * scale))
+		if v >= int64(scale) {
+			v = int64(scale) - 1
+		} else if v < -int64(scale) {
+			v = -int64(scale)
+		}
+
+		p := out[i*width : (i+1)*width]
+		switch f.BitsPerSample {
+		case 8:
+			p[0] = byte(v + 128)
+		case 16:
+			binary.LittleEndian.PutUint16(p, uint16(int16(v)))
+		case 24:
+			p[0], p[1], p[2] = byte(v), byte(v>>8), byte(v>>16)
+		case 32:
+			binary.LittleEndian.PutUint32(p, uint32(int32(v)))
+		}
+	}
+
+	return data, nil
+}
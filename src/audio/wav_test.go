@@ -0,0 +1,154 @@
+package audio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// samples are the values of every fixture, they are exact in every format.
+var samples = []float64{0, 0.5, -0.5, 0.25, -1, 0.75, -0.25, 0.125, -0.125, 0.625, -0.875, 0.375, 0.0625, -0.0625, 0.9921875, -0.75}
+
+func reversed(s []float64) []float64 {
+	out := make([]float64, len(s))
+	for i, v := range s {
+		out[len(s)-1-i] = v
+	}
+	return out
+}
+
+func interleave(channels ...[]float64) []float64 {
+	out := []float64{}
+	for i := range channels[0] {
+		for _, c := range channels {
+			out = append(out, c[i])
+		}
+	}
+	return out
+}
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func decode(t *testing.T, name string) *Buffer {
+	t.Helper()
+	b, err := Decode(fixture(t, name))
+	if err != nil {
+		t.Fatalf("decode %s: %s", name, err)
+	}
+	return b
+}
+
+func encode(t *testing.T, b *Buffer, f Format) []byte {
+	t.Helper()
+	data, err := Encode(b, f)
+	if err != nil {
+		t.Fatalf("encode: %s", err)
+	}
+	return data
+}
+
+func equalSamples(t *testing.T, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("sample %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	stereo := interleave(samples, reversed(samples))
+	tests := []struct {
+		name       string
+		sampleRate int
+		channels   int
+		samples    []float64
+	}{
+		{"mono16.wav", 22050, 1, samples},
+		{"stereo8.wav", 11025, 2, stereo},
+		{"mono24.wav", 44100, 1, samples},
+		{"mono32.wav", 22050, 1, samples},
+		// has a padded LIST chunk before the data.
+		{"stereo_float32.wav", 44100, 2, stereo},
+		{"mono_float64.wav", 22050, 1, samples},
+		{"extensible16.wav", 22050, 1, samples},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := decode(t, tt.name)
+			if b.SampleRate != tt.sampleRate || b.Channels != tt.channels {
+				t.Fatalf("got %d Hz with %d channels, want %d Hz with %d channels", b.SampleRate, b.Channels, tt.sampleRate, tt.channels)
+			}
+			equalSamples(t, b.Samples, tt.samples)
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	mono := fixture(t, "mono16.wav")
+	noData := append([]byte{}, mono[:36]...)
+
+	for name, data := range map[string][]byte{
+		"empty":   nil,
+		"no riff": append([]byte("RIFX"), mono[4:]...),
+		"no data": noData,
+	} {
+		if _, err := Decode(data); err == nil {
+			t.Errorf("%s: decoded an invalid wav", name)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format Format
+		want   string
+	}{
+		{"round trip 16 bit", "mono16.wav", Format{22050, 1, 16}, "mono16.wav"},
+		{"round trip 8 bit stereo", "stereo8.wav", Format{11025, 2, 8}, "stereo8.wav"},
+		{"round trip 24 bit", "mono24.wav", Format{44100, 1, 24}, "mono24.wav"},
+		{"round trip 32 bit", "mono32.wav", Format{22050, 1, 32}, "mono32.wav"},
+		{"16 to 8 bit", "mono16.wav", Format{22050, 1, 8}, "mono16_8.wav"},
+		{"16 to 24 bit", "mono16.wav", Format{22050, 1, 24}, "mono16_24.wav"},
+		{"float to 16 bit", "stereo_float32.wav", Format{44100, 2, 16}, "stereo_float32_stereo16_44100.wav"},
+		{"extensible to pcm", "extensible16.wav", Format{22050, 1, 16}, "mono16.wav"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encode(t, decode(t, tt.input), tt.format)
+			if !bytes.Equal(got, fixture(t, tt.want)) {
+				t.Fatalf("output differs from %s", tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeClamps(t *testing.T) {
+	data := encode(t, &Buffer{SampleRate: 22050, Channels: 1, Samples: []float64{2, -2}}, Format{22050, 1, 16})
+	b, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalSamples(t, b.Samples, []float64{32767.0 / 32768, -1})
+}
+
+func TestEncodeUnsupported(t *testing.T) {
+	if _, err := Encode(&Buffer{SampleRate: 22050, Channels: 1}, Format{22050, 1, 12}); err == nil {
+		t.Fatal("encoded 12 bit audio")
+	}
+}
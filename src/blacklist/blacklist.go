@@ -0,0 +1,140 @@
+package blacklist
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	matcher "github.com/admiralbulldogtv/yappercontroller/src/textparser/blacklist"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type blacklistInstance struct {
+	gCtx     global.Context
+	mtx      sync.RWMutex
+	global   matcher.List
+	channels map[primitive.ObjectID]matcher.List
+}
+
+// NewInstance loads the blacklist from mongo and keeps it up to date,
+// an empty blacklist is seeded with the built in defaults so they can be edited.
+func NewInstance(ctx global.Context) (instance.Blacklist, error) {
+	inst := &blacklistInstance{
+		gCtx: ctx,
+	}
+
+	entries, err := ctx.Inst().Mongo.FetchBlacklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		for _, e := range matcher.Defaults {
+			e.ID = primitive.NewObjectIDFromTimestamp(time.Now())
+			e.CreatedAt = time.Now()
+			if err := ctx.Inst().Mongo.InsertBlacklistEntry(ctx, e); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := inst.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		tick := time.NewTicker(time.Minute)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				if err := inst.reload(ctx); err != nil {
+					logrus.WithError(err).Error("failed to reload blacklist")
+				}
+			}
+		}
+	}()
+
+	return inst, nil
+}
+
+func (inst *blacklistInstance) reload(ctx context.Context) error {
+	entries, err := inst.gCtx.Inst().Mongo.FetchBlacklist(ctx)
+	if err != nil {
+		return err
+	}
+
+	global := matcher.List{}
+	channels := map[primitive.ObjectID]matcher.List{}
+	for _, e := range entries {
+		// a single bad entry should not disable the whole blacklist.
+		l, err := matcher.Compile([]datastructures.BlacklistEntry{e})
+		if err != nil {
+			logrus.WithError(err).WithField("id", e.ID.Hex()).Error("bad blacklist entry")
+			continue
+		}
+		if e.ChannelID.IsZero() {
+			global = append(global, l...)
+		} else {
+			channels[e.ChannelID] = append(channels[e.ChannelID], l...)
+		}
+	}
+
+	inst.mtx.Lock()
+	inst.global = global
+	inst.channels = channels
+	inst.mtx.Unlock()
+
+	return nil
+}
+
+func (inst *blacklistInstance) For(channelID primitive.ObjectID) matcher.List {
+	inst.mtx.RLock()
+	defer inst.mtx.RUnlock()
+
+	return append(append(matcher.List{}, inst.global...), inst.channels[channelID]...)
+}
+
+// Entries returns the stored entries of a channel, the global entries are returned for a zero channel id.
+func (inst *blacklistInstance) Entries(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.BlacklistEntry, error) {
+	entries, err := inst.gCtx.Inst().Mongo.FetchBlacklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []datastructures.BlacklistEntry{}
+	for _, e := range entries {
+		if e.ChannelID == channelID {
+			out = append(out, e)
+		}
+	}
+
+	return out, nil
+}
+
+func (inst *blacklistInstance) Add(ctx context.Context, entry datastructures.BlacklistEntry) (datastructures.BlacklistEntry, error) {
+	if _, err := matcher.Compile([]datastructures.BlacklistEntry{entry}); err != nil {
+		return entry, err
+	}
+
+	entry.ID = primitive.NewObjectIDFromTimestamp(time.Now())
+	entry.CreatedAt = time.Now()
+	if err := inst.gCtx.Inst().Mongo.InsertBlacklistEntry(ctx, entry); err != nil {
+		return entry, err
+	}
+
+	return entry, inst.reload(ctx)
+}
+
+func (inst *blacklistInstance) Remove(ctx context.Context, id primitive.ObjectID) error {
+	if err := inst.gCtx.Inst().Mongo.DeleteBlacklistEntry(ctx, id); err != nil {
+		return err
+	}
+
+	return inst.reload(ctx)
+}
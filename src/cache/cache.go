@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	"github.com/admiralbulldogtv/yappercontroller/src/utils"
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// entryKey holds the audio of a segment.
+	entryKey = "tts:cache:entry:%s"
+	// lruKey scores every entry by when it was last used.
+	lruKey = "tts:cache:lru"
+	// statsKey counts hits, misses and the size of all entries.
+	statsKey = "tts:cache:stats"
+)
+
+type cacheInstance struct {
+	gCtx       global.Context
+	enabled    bool
+	maxBytes   int64
+	maxEntries int64
+}
+
+// NewInstance creates a cache of generated segments which is shared by every controller through redis.
+func NewInstance(ctx global.Context) (instance.Cache, error) {
+	cfg := ctx.Config().TTS.Cache
+	inst := &cacheInstance{
+		gCtx:       ctx,
+		enabled:    cfg.Enabled,
+		maxBytes:   cfg.MaxBytes,
+		maxEntries: cfg.MaxEntries,
+	}
+	if inst.maxBytes <= 0 {
+		inst.maxBytes = 256 << 20
+	}
+	if inst.maxEntries <= 0 {
+		inst.maxEntries = 20000
+	}
+
+	return inst, nil
+}
+
+func (inst *cacheInstance) count(ctx context.Context, field string, n int64) {
+	if _, err := inst.gCtx.Inst().Redis.HIncrBy(ctx, statsKey, field, n); err != nil {
+		logrus.WithError(err).Error("failed to update cache stats")
+	}
+}
+
+// Get returns the audio of a cached segment, errors are logged and treated as a miss.
+func (inst *cacheInstance) Get(ctx context.Context, key string) ([]byte, bool) {
+	if !inst.enabled {
+		return nil, false
+	}
+
+	r := inst.gCtx.Inst().Redis
+	data, err := r.Get(ctx, fmt.Sprintf(entryKey, key))
+	if err != nil {
+		if err != redis.Nil {
+			logrus.WithError(err).Error("failed to read cached segment")
+		}
+		inst.count(ctx, "misses", 1)
+		return nil, false
+	}
+
+	if err := r.ZAdd(ctx, lruKey, float64(time.Now().UnixNano()), key); err != nil {
+		logrus.WithError(err).Error("failed to touch cached segment")
+	}
+	inst.count(ctx, "hits", 1)
+
+	return utils.S2B(data), true
+}
+
+// Put stores the audio of a segment and evicts the least recently used segments while the cache is too big.
+func (inst *cacheInstance) Put(ctx context.Context, key string, data []byte) {
+	if !inst.enabled || int64(len(data)) > inst.maxBytes {
+		return
+	}
+
+	r := inst.gCtx.Inst().Redis
+	added, err := r.SetNX(ctx, fmt.Sprintf(entryKey, key), utils.B2S(data), 0)
+	if err != nil {
+		logrus.WithError(err).Error("failed to cache segment")
+		return
+	}
+	if err := r.ZAdd(ctx, lruKey, float64(time.Now().UnixNano()), key); err != nil {
+		logrus.WithError(err).Error("failed to cache segment")
+		return
+	}
+	if !added {
+		return
+	}
+
+	size, err := r.HIncrBy(ctx, statsKey, "bytes", int64(len(data)))
+	if err != nil {
+		logrus.WithError(err).Error("failed to update cache stats")
+		return
+	}
+	entries, err := r.ZCard(ctx, lruKey)
+	if err != nil {
+		logrus.WithError(err).Error("failed to read cache size")
+		return
+	}
+
+	for size > inst.maxBytes || entries > inst.maxEntries {
+		keys, err := r.ZPopMin(ctx, lruKey, 1)
+		if err != nil || len(keys) == 0 {
+			if err != nil {
+				logrus.WithError(err).Error("failed to evict cached segment")
+			}
+			return
+		}
+		entries--
+
+		freed, err := inst.remove(ctx, keys[0])
+		if err != nil {
+			logrus.WithError(err).Error("failed to evict cached segment")
+			return
+		}
+		size -= freed
+	}
+}
+
+// remove deletes an entry and returns how many bytes were freed.
+func (inst *cacheInstance) remove(ctx context.Context, key string) (int64, error) {
+	r := inst.gCtx.Inst().Redis
+	k := fmt.Sprintf(entryKey, key)
+
+	size, err := r.StrLen(ctx, k)
+	if err != nil {
+		return 0, err
+	}
+	// another controller might have evicted the entry first.
+	n, err := r.Del(ctx, k)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	if _, err = r.HIncrBy(ctx, statsKey, "bytes", -size); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+func (inst *cacheInstance) Stats(ctx context.Context) (datastructures.CacheStats, error) {
+	r := inst.gCtx.Inst().Redis
+	stats, err := r.HGetAll(ctx, statsKey)
+	if err != nil {
+		return datastructures.CacheStats{}, err
+	}
+	entries, err := r.ZCard(ctx, lruKey)
+	if err != nil {
+		return datastructures.CacheStats{}, err
+	}
+
+	parse := func(field string) int64 {
+		v, _ := strconv.ParseInt(stats[field], 10, 64)
+		return v
+	}
+
+	return datastructures.CacheStats{
+		Enabled: inst.enabled,
+		Hits:    parse("hits"),
+		Misses:  parse("misses"),
+		Entries: entries,
+		Bytes:   parse("bytes"),
+	}, nil
+}
+
+// Clear removes every entry and resets the stats.
+func (inst *cacheInstance) Clear(ctx context.Context) error {
+	r := inst.gCtx.Inst().Redis
+	for {
+		keys, err := r.ZPopMin(ctx, lruKey, 100)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			break
+		}
+		for i, k := range keys {
+			keys[i] = fmt.Sprintf(entryKey, k)
+		}
+		if _, err = r.Del(ctx, keys...); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.Del(ctx, statsKey)
+	return err
+}
@@ -0,0 +1,145 @@
+package channels
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type channelsInstance struct {
+	gCtx     global.Context
+	mtx      sync.RWMutex
+	channels []datastructures.Channel
+	watchers []chan struct{}
+}
+
+// NewInstance loads the channels from mongo and keeps them up to date.
+func NewInstance(ctx global.Context) (instance.Channels, error) {
+	inst := &channelsInstance{
+		gCtx: ctx,
+	}
+
+	if err := inst.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		tick := time.NewTicker(time.Minute)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				if err := inst.reload(ctx); err != nil {
+					logrus.WithError(err).Error("failed to reload channels")
+				}
+			}
+		}
+	}()
+
+	return inst, nil
+}
+
+func (inst *channelsInstance) reload(ctx context.Context) error {
+	channels, err := inst.gCtx.Inst().Mongo.FetchChannels(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(channels) == 0 {
+		if channel, ok := legacyChannel(inst.gCtx); ok {
+			channels = append(channels, channel)
+		}
+	}
+
+	inst.mtx.Lock()
+	inst.channels = channels
+	watchers := inst.watchers
+	inst.mtx.Unlock()
+
+	for _, ch := range watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Watch signals ch after every reload, a signal is dropped when ch is still full.
+func (inst *channelsInstance) Watch(ch chan struct{}) {
+	inst.mtx.Lock()
+	defer inst.mtx.Unlock()
+
+	inst.watchers = append(inst.watchers, ch)
+}
+
+// legacyChannel builds a channel from the single channel config used before channels were stored in mongo.
+func legacyChannel(ctx global.Context) (datastructures.Channel, bool) {
+	cfg := ctx.Config()
+	id, err := primitive.ObjectIDFromHex(cfg.TtsChannelID)
+	if err != nil {
+		return datastructures.Channel{}, false
+	}
+
+	login := cfg.Twitch.StreamerChannel
+	if login == "" {
+		login = cfg.Twitch.BotControlChannel
+	}
+
+	return datastructures.Channel{
+		ID:   id,
+		Name: login,
+		Twitch: datastructures.ChannelTwitch{
+			Login: login,
+		},
+		StreamElements: datastructures.ChannelStreamElements{
+			Enabled:    cfg.StreamElements.Enabled,
+			AuthMethod: cfg.StreamElements.AuthMethod,
+			AuthToken:  cfg.StreamElements.AuthToken,
+		},
+		WhitelistedAccounts: cfg.Twitch.WhitelistedAccounts,
+	}, true
+}
+
+func (inst *channelsInstance) All() []datastructures.Channel {
+	inst.mtx.RLock()
+	defer inst.mtx.RUnlock()
+
+	return append([]datastructures.Channel{}, inst.channels...)
+}
+
+func (inst *channelsInstance) Get(id primitive.ObjectID) (datastructures.Channel, bool) {
+	inst.mtx.RLock()
+	defer inst.mtx.RUnlock()
+
+	for _, v := range inst.channels {
+		if v.ID == id {
+			return v, true
+		}
+	}
+
+	return datastructures.Channel{}, false
+}
+
+func (inst *channelsInstance) ByLogin(login string) (datastructures.Channel, bool) {
+	inst.mtx.RLock()
+	defer inst.mtx.RUnlock()
+
+	for _, v := range inst.channels {
+		if v.Twitch.Login != "" && strings.EqualFold(v.Twitch.Login, login) {
+			return v, true
+		}
+	}
+
+	return datastructures.Channel{}, false
+}
@@ -89,14 +89,18 @@ type Config struct {
 	TtsChannelID string `mapstructure:"tts_channel_id" json:"tts_channel_id"`
 
 	Redis struct {
-		Username    string   `mapstructure:"username" json:"username"`
-		Password    string   `mapstructure:"password" json:"password"`
-		MasterName  string   `mapstructure:"master_name" json:"master_name"`
-		Addresses   []string `mapstructure:"addresses" json:"addresses"`
-		Database    int      `mapstructure:"database" json:"database"`
-		Sentinel    bool     `mapstructure:"sentinel" json:"sentinel"`
-		TaskSetKey  string   `mapstructure:"task_set_key" json:"task_set_key"`
-		OutputEvent string   `mapstructure:"output_event" json:"output_event"`
+		Username       string   `mapstructure:"username" json:"username"`
+		Password       string   `mapstructure:"password" json:"password"`
+		MasterName     string   `mapstructure:"master_name" json:"master_name"`
+		Addresses      []string `mapstructure:"addresses" json:"addresses"`
+		Database       int      `mapstructure:"database" json:"database"`
+		Sentinel       bool     `mapstructure:"sentinel" json:"sentinel"`
+		TaskStream     string   `mapstructure:"task_stream" json:"task_stream"`
+		WorkerGroup    string   `mapstructure:"worker_group" json:"worker_group"`
+		OutputStream   string   `mapstructure:"output_stream" json:"output_stream"`
+		HeartbeatEvent string   `mapstructure:"heartbeat_event" json:"heartbeat_event"`
+		// ControllerID names the result stream of this controller, it has to be unique per replica.
+		ControllerID string `mapstructure:"controller_id" json:"controller_id"`
 	} `mapstructure:"redis" json:"redis"`
 
 	Mongo struct {
@@ -104,6 +108,61 @@ type Config struct {
 		Database string `mapstructure:"database" json:"database"`
 	} `mapstructure:"mongo" json:"mongo"`
 
+	Rules struct {
+		File            string `mapstructure:"file" json:"file"`
+		RefreshInterval int    `mapstructure:"refresh_interval" json:"refresh_interval"`
+	} `mapstructure:"rules" json:"rules"`
+
+	Currency struct {
+		Base            string             `mapstructure:"base" json:"base"`
+		RatesFile       string             `mapstructure:"rates_file" json:"rates_file"`
+		Rates           map[string]float64 `mapstructure:"rates" json:"rates"`
+		RefreshInterval int                `mapstructure:"refresh_interval" json:"refresh_interval"`
+	} `mapstructure:"currency" json:"currency"`
+
+	TextParser struct {
+		MaxUnreadable    float64 `mapstructure:"max_unreadable" json:"max_unreadable"`
+		RejectUnreadable bool    `mapstructure:"reject_unreadable" json:"reject_unreadable"`
+		Spam             struct {
+			Chars  int `mapstructure:"chars" json:"chars"`
+			Words  int `mapstructure:"words" json:"words"`
+			Ngrams int `mapstructure:"ngrams" json:"ngrams"`
+		} `mapstructure:"spam" json:"spam"`
+	} `mapstructure:"textparser" json:"textparser"`
+
+	VoicePools struct {
+		NoRepeat bool `mapstructure:"no_repeat" json:"no_repeat"`
+		Pools    map[string][]struct {
+			Voice  string `mapstructure:"voice" json:"voice"`
+			Weight int    `mapstructure:"weight" json:"weight"`
+		} `mapstructure:"pools" json:"pools"`
+		Events map[string]string `mapstructure:"events" json:"events"`
+	} `mapstructure:"voice_pools" json:"voice_pools"`
+
+	Events struct {
+		DedupWindow int `mapstructure:"dedup_window" json:"dedup_window"`
+	} `mapstructure:"events" json:"events"`
+
+	TTS struct {
+		// JobTimeout is how many seconds a worker has to answer a job before it is submitted again.
+		JobTimeout int `mapstructure:"job_timeout" json:"job_timeout"`
+		JobRetries int `mapstructure:"job_retries" json:"job_retries"`
+		// WorkerTimeout is how many seconds a worker can miss heartbeats before no more jobs are sent to it.
+		WorkerTimeout int `mapstructure:"worker_timeout" json:"worker_timeout"`
+
+		Cache struct {
+			Enabled    bool  `mapstructure:"enabled" json:"enabled"`
+			MaxBytes   int64 `mapstructure:"max_bytes" json:"max_bytes"`
+			MaxEntries int64 `mapstructure:"max_entries" json:"max_entries"`
+		} `mapstructure:"cache" json:"cache"`
+	} `mapstructure:"tts" json:"tts"`
+
+	DeadLetters struct {
+		MaxAttempts   int `mapstructure:"max_attempts" json:"max_attempts"`
+		RetryDelay    int `mapstructure:"retry_delay" json:"retry_delay"`
+		MaxRetryDelay int `mapstructure:"max_retry_delay" json:"max_retry_delay"`
+	} `mapstructure:"dead_letters" json:"dead_letters"`
+
 	StreamElements struct {
 		Enabled    bool   `mapstructure:"enabled" json:"enabled"`
 		WssURL     string `mapstructure:"wss_url" json:"wss_url"`
@@ -0,0 +1,32 @@
+package datastructures
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	BlacklistTypeWord  = "word"
+	BlacklistTypeRegex = "regex"
+)
+
+const (
+	// BlacklistSeverityReject refuses the whole message.
+	BlacklistSeverityReject = "reject"
+	// BlacklistSeverityBleep bleeps out the match.
+	BlacklistSeverityBleep = "bleep"
+	// BlacklistSeverityReplace replaces the match with the replacement of the entry.
+	BlacklistSeverityReplace = "replace"
+)
+
+// BlacklistEntry is a phrase which is not allowed to be read out, entries without a channel apply to every channel.
+type BlacklistEntry struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	ChannelID   primitive.ObjectID `bson:"channel_id" json:"channel_id"`
+	Type        string             `bson:"type" json:"type"`
+	Pattern     string             `bson:"pattern" json:"pattern"`
+	Severity    string             `bson:"severity" json:"severity"`
+	Replacement string             `bson:"replacement,omitempty" json:"replacement,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,68 @@
+package datastructures
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Channel is a streamer served by this controller, overlays reference it through their channel id.
+type Channel struct {
+	ID                  primitive.ObjectID    `bson:"_id" json:"_id"`
+	Name                string                `bson:"name" json:"name"`
+	Twitch              ChannelTwitch         `bson:"twitch" json:"twitch"`
+	StreamElements      ChannelStreamElements `bson:"streamelements" json:"streamelements"`
+	WhitelistedAccounts []string              `bson:"whitelisted_accounts" json:"whitelisted_accounts"`
+	// Voices limits the voices this channel can use, when empty every voice can be used.
+	Voices []string    `bson:"voices" json:"voices"`
+	Hold   ChannelHold `bson:"hold" json:"hold"`
+	// Links is either "read" or "drop", links are read when empty.
+	Links string      `bson:"links" json:"links"`
+	Spam  ChannelSpam `bson:"spam" json:"spam"`
+}
+
+const (
+	ChannelHoldActionApprove = "approve"
+	ChannelHoldActionReject  = "reject"
+)
+
+// ChannelHold keeps paid messages pending until a moderator approves, edits or rejects them.
+type ChannelHold struct {
+	Enabled bool `bson:"enabled" json:"enabled"`
+	// Timeout in seconds after which Action is applied to a pending message.
+	Timeout int    `bson:"timeout" json:"timeout"`
+	Action  string `bson:"action" json:"action"`
+}
+
+// SpamLimits are how often characters, words and groups of words can repeat in a row,
+// zero uses the configured default and a negative limit disables it.
+type SpamLimits struct {
+	Chars  int `bson:"chars" json:"chars"`
+	Words  int `bson:"words" json:"words"`
+	Ngrams int `bson:"ngrams" json:"ngrams"`
+}
+
+// ChannelSpam holds the spam limits of a channel for cheers, donations and manual messages.
+type ChannelSpam struct {
+	Bits     SpamLimits `bson:"bits" json:"bits"`
+	Donation SpamLimits `bson:"donation" json:"donation"`
+	Manual   SpamLimits `bson:"manual" json:"manual"`
+}
+
+// For returns the limits for an AudioTrigger source, other sources use the donation limits.
+func (s ChannelSpam) For(source string) SpamLimits {
+	switch source {
+	case AudioTriggerSourceBits:
+		return s.Bits
+	case AudioTriggerSourceManual:
+		return s.Manual
+	default:
+		return s.Donation
+	}
+}
+
+type ChannelTwitch struct {
+	Login string `bson:"login" json:"login"`
+}
+
+type ChannelStreamElements struct {
+	Enabled    bool   `bson:"enabled" json:"enabled"`
+	AuthMethod string `bson:"auth_method" json:"auth_method"`
+	AuthToken  string `bson:"auth_token" json:"-"`
+}
@@ -0,0 +1,26 @@
+package datastructures
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DeadLetterStatePending   = "PENDING"
+	DeadLetterStateRetrying  = "RETRYING"
+	DeadLetterStateExhausted = "EXHAUSTED"
+)
+
+// DeadLetter is a request which failed to generate, it shares its id with the request.
+type DeadLetter struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	ChannelID   primitive.ObjectID `bson:"channel_id" json:"channel_id"`
+	Request     TtsRequest         `bson:"request" json:"request"`
+	Error       string             `bson:"error" json:"error"`
+	State       string             `bson:"state" json:"state"`
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	NextAttempt time.Time          `bson:"next_attempt" json:"next_attempt"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
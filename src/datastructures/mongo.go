@@ -55,12 +55,17 @@ const (
 	AudioTriggerSourceSub      = "SUBSCRIPTION"
 	AudioTriggerSourceDonation = "DONATION"
 	AudioTriggerSourceBits     = "BITS"
+	AudioTriggerSourceGift     = "GIFT"
 )
 
 type AudioTrigger struct {
-	Source   string `bson:"source" json:"source"`
-	Username string `bson:"username" json:"username"`
-	Bits     int    `bson:"bits" json:"bits"`
-	Amount   int    `bson:"donation" json:"donation"`
-	Currency string `bson:"currency" json:"currency"`
+	Source    string  `bson:"source" json:"source"`
+	Username  string  `bson:"username" json:"username"`
+	Bits      int     `bson:"bits" json:"bits"`
+	Amount    float64 `bson:"donation" json:"donation"`
+	Currency  string  `bson:"currency" json:"currency"`
+	Months    int     `bson:"months,omitempty" json:"months,omitempty"`
+	Tier      string  `bson:"tier,omitempty" json:"tier,omitempty"`
+	Gifts     int     `bson:"gifts,omitempty" json:"gifts,omitempty"`
+	Recipient string  `bson:"recipient,omitempty" json:"recipient,omitempty"`
 }
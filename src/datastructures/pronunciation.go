@@ -0,0 +1,26 @@
+package datastructures
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// PronunciationTypeWord reads the replacement instead of the word, "|" splits syllables.
+	PronunciationTypeWord = "word"
+	// PronunciationTypeLetters spells the word as the letters and words of the replacement.
+	PronunciationTypeLetters = "letters"
+	// PronunciationTypeRegex replaces every match of the pattern, the replacement can reference groups like $1.
+	PronunciationTypeRegex = "regex"
+)
+
+// Pronunciation is an entry of the pronunciation dictionary, entries without a channel apply to every channel.
+type Pronunciation struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	ChannelID   primitive.ObjectID `bson:"channel_id" json:"channel_id"`
+	Type        string             `bson:"type" json:"type"`
+	Pattern     string             `bson:"pattern" json:"pattern"`
+	Replacement string             `bson:"replacement" json:"replacement"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
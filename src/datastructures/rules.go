@@ -0,0 +1,39 @@
+package datastructures
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	AlertTypeCheer      = "cheer"
+	AlertTypeDonation   = "donation"
+	AlertTypeSubscriber = "subscriber"
+	AlertTypeGift       = "gift"
+)
+
+// AlertRules describes how a single event type is turned into an alert.
+// Tiers are evaluated in order, every matching tier overrides the alert and volume
+// and adds its voices to the ones already unlocked.
+// Type is the alert asset group and defaults to the event.
+type AlertRules struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Event   string             `bson:"event" json:"event"`
+	Type    string             `bson:"type,omitempty" json:"type,omitempty"`
+	Minimum float64            `bson:"minimum" json:"minimum"`
+	Alert   string             `bson:"alert" json:"alert"`
+	Voices  []string           `bson:"voices" json:"voices"`
+	// Markup are the markup tags messages can use, tiers add to them.
+	Markup []string `bson:"markup,omitempty" json:"markup,omitempty"`
+	// Sounds are the sound bytes messages can use, tiers add to them.
+	Sounds []string        `bson:"sounds,omitempty" json:"sounds,omitempty"`
+	Tiers  []AlertRuleTier `bson:"tiers" json:"tiers"`
+}
+
+type AlertRuleTier struct {
+	Amount float64  `bson:"amount" json:"amount"`
+	Exact  bool     `bson:"exact,omitempty" json:"exact,omitempty"`
+	Tier   string   `bson:"tier,omitempty" json:"tier,omitempty"`
+	Alert  string   `bson:"alert,omitempty" json:"alert,omitempty"`
+	Volume int      `bson:"volume,omitempty" json:"volume,omitempty"`
+	Voices []string `bson:"voices,omitempty" json:"voices,omitempty"`
+	Markup []string `bson:"markup,omitempty" json:"markup,omitempty"`
+	Sounds []string `bson:"sounds,omitempty" json:"sounds,omitempty"`
+}
@@ -0,0 +1,27 @@
+package datastructures
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// TtsRequest is everything needed to synthesize a message and show its alert.
+// Voices are referenced by name so requests can be stored and replayed.
+type TtsRequest struct {
+	ID            primitive.ObjectID `bson:"id" json:"id"`
+	ChannelID     primitive.ObjectID `bson:"channel_id" json:"channel_id"`
+	Text          string             `bson:"text" json:"text"`
+	Voice         string             `bson:"voice" json:"voice"`
+	ValidVoices   []string           `bson:"valid_voices" json:"valid_voices"`
+	MaxVoiceSwaps int                `bson:"max_voice_swaps" json:"max_voice_swaps"`
+	Markup        []string           `bson:"markup" json:"markup"`
+	Sounds        []string           `bson:"sounds" json:"sounds"`
+	Alert         *SseEventTtsAlert  `bson:"alert" json:"alert"`
+	Trigger       AudioTrigger       `bson:"trigger" json:"trigger"`
+}
+
+// CacheStats describes the cache of generated segments, hits and misses are counted since the cache was last cleared.
+type CacheStats struct {
+	Enabled bool  `json:"enabled"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int64 `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+}
@@ -0,0 +1,62 @@
+package datastructures
+
+import (
+	"fmt"
+	"time"
+)
+
+// Worker is a tts worker as advertised by its heartbeats. Workers only read as many jobs from their streams
+// as they can run at once, so the controller does not need to know their load.
+type Worker struct {
+	ID string `json:"id"`
+	// Voices are the speakers the worker has loaded.
+	Voices []string `json:"voices"`
+	// Modes are the tts modes the worker can generate, precise (0) and fast (1).
+	Modes []int32 `json:"modes"`
+	GPU   bool    `json:"gpu"`
+
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// WorkerRequirements is what a worker needs to generate a job.
+type WorkerRequirements struct {
+	Voice string
+	Mode  int32
+	GPU   bool
+}
+
+// WorkerStream is the job stream of a capability, <stream>:<mode>:<voice> with a :gpu suffix for jobs which need a gpu.
+// Workers read the stream of every voice and mode they support, workers with a gpu read the gpu streams too.
+func WorkerStream(stream string, req WorkerRequirements) string {
+	stream = fmt.Sprintf("%s:%d:%s", stream, req.Mode, req.Voice)
+	if req.GPU {
+		stream += ":gpu"
+	}
+	return stream
+}
+
+// Can checks if the worker is able to generate a job.
+func (w Worker) Can(req WorkerRequirements) bool {
+	if req.GPU && !w.GPU {
+		return false
+	}
+
+	mode := false
+	for _, m := range w.Modes {
+		if m == req.Mode {
+			mode = true
+			break
+		}
+	}
+	if !mode {
+		return false
+	}
+
+	for _, v := range w.Voices {
+		if v == req.Voice {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,179 @@
+package deadletters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	"github.com/admiralbulldogtv/yappercontroller/src/queue"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrRetrying is returned when a letter is already being retried, possibly by another controller.
+var ErrRetrying = fmt.Errorf("dead letter is already being retried")
+
+type deadLettersInstance struct {
+	gCtx global.Context
+}
+
+// NewInstance keeps every failed generation and retries it with an exponential backoff,
+// once the attempts are used up the letter stays until an operator retries or discards it.
+func NewInstance(ctx global.Context) (instance.DeadLetters, error) {
+	inst := &deadLettersInstance{
+		gCtx: ctx,
+	}
+
+	go func() {
+		tick := time.NewTicker(time.Second * 10)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				inst.retryDue()
+			}
+		}
+	}()
+
+	return inst, nil
+}
+
+func (inst *deadLettersInstance) maxAttempts() int {
+	if v := inst.gCtx.Config().DeadLetters.MaxAttempts; v > 0 {
+		return v
+	}
+	return 5
+}
+
+// backoff is the delay before the next attempt, doubling with every failed attempt.
+func (inst *deadLettersInstance) backoff(attempts int) time.Duration {
+	cfg := inst.gCtx.Config().DeadLetters
+	delay := time.Duration(cfg.RetryDelay) * time.Second
+	if delay <= 0 {
+		delay = time.Second * 30
+	}
+	max := time.Duration(cfg.MaxRetryDelay) * time.Second
+	if max <= 0 {
+		max = time.Minute * 30
+	}
+
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	return delay
+}
+
+func (inst *deadLettersInstance) Capture(ctx context.Context, req datastructures.TtsRequest, cause error) error {
+	mgo := inst.gCtx.Inst().Mongo
+	letter, err := mgo.FetchDeadLetter(ctx, req.ID)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			return err
+		}
+		letter = datastructures.DeadLetter{
+			ID:        req.ID,
+			ChannelID: req.ChannelID,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	letter.Request = req
+	letter.Error = cause.Error()
+	letter.Attempts++
+	letter.UpdatedAt = time.Now()
+	if letter.Attempts >= inst.maxAttempts() {
+		letter.State = datastructures.DeadLetterStateExhausted
+		letter.NextAttempt = time.Time{}
+	} else {
+		letter.State = datastructures.DeadLetterStatePending
+		letter.NextAttempt = letter.UpdatedAt.Add(inst.backoff(letter.Attempts))
+	}
+
+	return mgo.SaveDeadLetter(ctx, letter)
+}
+
+func (inst *deadLettersInstance) Resolve(ctx context.Context, id primitive.ObjectID) error {
+	return inst.gCtx.Inst().Mongo.DeleteDeadLetter(ctx, id)
+}
+
+func (inst *deadLettersInstance) List(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.DeadLetter, error) {
+	return inst.gCtx.Inst().Mongo.FetchDeadLetters(ctx, channelID)
+}
+
+func (inst *deadLettersInstance) Retry(ctx context.Context, id primitive.ObjectID) error {
+	letter, err := inst.gCtx.Inst().Mongo.FetchDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return inst.retry(ctx, letter)
+}
+
+// Discard deletes a letter, a retry which is still running is cancelled.
+func (inst *deadLettersInstance) Discard(ctx context.Context, id primitive.ObjectID) error {
+	letter, err := inst.gCtx.Inst().Mongo.FetchDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if letter.State == datastructures.DeadLetterStateRetrying {
+		if err := inst.gCtx.Inst().Queue.Skip(ctx, letter.ChannelID, letter.ID); err != nil && err != queue.ErrNotFound {
+			return err
+		}
+	}
+
+	return inst.gCtx.Inst().Mongo.DeleteDeadLetter(ctx, id)
+}
+
+// retry queues the request again, the queue resolves or captures the letter once it has been synthesized.
+// Only the controller which moves the letter to retrying queues it.
+func (inst *deadLettersInstance) retry(ctx context.Context, letter datastructures.DeadLetter) error {
+	mgo := inst.gCtx.Inst().Mongo
+
+	if letter.State == datastructures.DeadLetterStateRetrying {
+		return ErrRetrying
+	}
+	old := letter
+	letter.State = datastructures.DeadLetterStateRetrying
+	letter.UpdatedAt = time.Now()
+	if ok, err := mgo.SwapDeadLetter(ctx, old, letter); err != nil {
+		return err
+	} else if !ok {
+		return ErrRetrying
+	}
+
+	if _, err := inst.gCtx.Inst().Queue.Enqueue(ctx, letter.Request); err != nil {
+		letter.State = datastructures.DeadLetterStatePending
+		letter.NextAttempt = time.Now().Add(inst.backoff(letter.Attempts))
+		if err := mgo.SaveDeadLetter(ctx, letter); err != nil {
+			logrus.WithError(err).Error("failed to update dead letter")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (inst *deadLettersInstance) retryDue() {
+	ctx := inst.gCtx
+	letters, err := ctx.Inst().Mongo.FetchDueDeadLetters(ctx, time.Now())
+	if err != nil {
+		logrus.WithError(err).Error("failed to fetch dead letters")
+		return
+	}
+
+	for _, v := range letters {
+		if err := inst.retry(ctx, v); err != nil && err != ErrRetrying {
+			logrus.WithError(err).WithField("id", v.ID.Hex()).Error("failed to retry dead letter")
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package events
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/exchange"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/rules"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/currency"
+	"github.com/admiralbulldogtv/yappercontroller/src/voicepool"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var bitsRe = regexp.MustCompile(`(?i)\b(?:Cheer|BibleThump|cheerwhal|Corgo|uni|ShowLove|Party|SeemsGood|Pride|Kappa|FrankerZ|HeyGuys|DansGame|EleGiggle|TriHard|Kreygasm|4Head|SwiftRage|NotLikeThis|FailFish|VoHiYo|PJSalt|MrDestructoid|bday|RIPCheer|Shamrock)\d+\b`)
+
+// Dispatcher turns the events of every source into alerts.
+type Dispatcher struct {
+	gCtx   global.Context
+	rules  *rules.Store
+	rates  *exchange.Rates
+	voices *voicepool.Roulette
+}
+
+func NewDispatcher(ctx global.Context, rules *rules.Store, rates *exchange.Rates, voices *voicepool.Roulette) *Dispatcher {
+	return &Dispatcher{
+		gCtx:   ctx,
+		rules:  rules,
+		rates:  rates,
+		voices: voices,
+	}
+}
+
+// Run handles the events of the sources until their events channel is closed,
+// sources which are started later are added with another Run.
+func (d *Dispatcher) Run(sources ...Source) {
+	for _, src := range sources {
+		go func(src Source) {
+			ch := src.Events()
+			for {
+				select {
+				case <-d.gCtx.Done():
+					return
+				case event, ok := <-ch:
+					if !ok {
+						return
+					}
+					d.handle(src.Name(), event)
+				}
+			}
+		}(src)
+	}
+}
+
+// duplicate checks if the gift has already been reported by another source within the dedup window.
+// Gifts are matched by their gifter and amount, the nth gift a source reports is a duplicate
+// when another source already reported its nth gift.
+func (d *Dispatcher) duplicate(source string, event Event) bool {
+	window := time.Duration(d.gCtx.Config().Events.DedupWindow) * time.Second
+	if window <= 0 {
+		window = time.Minute * 2
+	}
+	r := d.gCtx.Inst().Redis
+
+	key := fmt.Sprintf("events:dedup:%s:%s:%d",
+		event.ChannelID.Hex(),
+		strings.ToLower(event.Login),
+		event.Gifts,
+	)
+
+	n, err := r.HIncrBy(d.gCtx, key, source, 1)
+	if err == nil {
+		err = r.Expire(d.gCtx, key, window)
+	}
+	var ok bool
+	if err == nil {
+		ok, err = r.SetNX(d.gCtx, fmt.Sprintf("%s:%d", key, n), source, window)
+	}
+	if err != nil {
+		// rather alert twice than not at all.
+		logrus.WithError(err).Error("failed to dedup event")
+		return false
+	}
+
+	return !ok
+}
+
+func (d *Dispatcher) handle(source string, event Event) {
+	var (
+		message   = event.Message
+		subText   = event.Message
+		alertText string
+		alertType string
+		result    rules.Result
+	)
+
+	switch event.Source {
+	case datastructures.AudioTriggerSourceBits:
+		alertText = fmt.Sprintf("~%s cheered ~%d bits", event.Username, event.Bits)
+
+		// filter bit emotes
+		message = bitsRe.ReplaceAllString(message, "")
+
+		alertType = datastructures.AlertTypeCheer
+		result = d.rules.Evaluate(alertType, float64(event.Bits), "")
+	case datastructures.AudioTriggerSourceDonation:
+		if event.Currency == "" {
+			event.Currency = d.rates.Base()
+		}
+		alertText = fmt.Sprintf("~%s donated ~%s", event.Username, currency.FormatAmount(event.Amount, event.Currency))
+
+		// tiers are always in the base currency.
+		amount, err := d.rates.ToBase(event.Amount, event.Currency)
+		if err != nil {
+			logrus.WithError(err).Warnf("cannot convert %s, using the raw amount", event.Currency)
+			amount = event.Amount
+		}
+
+		alertType = datastructures.AlertTypeDonation
+		result = d.rules.Evaluate(alertType, amount, "")
+	case datastructures.AudioTriggerSourceSub:
+		alertText = fmt.Sprintf("~%s subscribed for ~%d months", event.Username, event.Months)
+		if event.Months == 1 {
+			alertText = fmt.Sprintf("~%s just subscribed", event.Username)
+		}
+
+		alertType = datastructures.AlertTypeSubscriber
+		result = d.rules.Evaluate(alertType, float64(event.Months), event.Tier)
+
+		// because I promised I would do it to him
+		if event.Username == "pyra____" {
+			result.Voices = append(result.Voices, "gura")
+			subText = "gura: chat I am not the biggest weeb here, I am actually the furry dancing in the skyline video."
+			message = subText
+		}
+	case datastructures.AudioTriggerSourceGift:
+		if d.duplicate(source, event) {
+			logrus.Infof("ignoring duplicate gift from %s", source)
+			return
+		}
+
+		// gifts have no message to read out.
+		message = ""
+		subText = ""
+
+		if event.Gifts > 1 {
+			alertText = fmt.Sprintf("~%s gifted ~%d subs", event.Username, event.Gifts)
+			if event.Tier != "" && event.Tier != "1000" {
+				alertText = fmt.Sprintf("~%s gifted ~%d tier %s subs", event.Username, event.Gifts, string(event.Tier[0]))
+			}
+		} else {
+			alertText = fmt.Sprintf("~%s gifted a sub to ~%s", event.Username, event.Recipient)
+			if event.Tier != "" && event.Tier != "1000" {
+				alertText = fmt.Sprintf("~%s gifted a tier %s sub to %s", event.Username, string(event.Tier[0]), event.Recipient)
+			}
+		}
+
+		if event.SenderCount != 0 {
+			subText = fmt.Sprintf("they have gifted %d subs to the channel", event.SenderCount)
+			if event.SenderCount == 1 {
+				subText = "this is their first gifted sub"
+			}
+		}
+
+		alertType = datastructures.AlertTypeGift
+		result = d.rules.Evaluate(alertType, float64(event.Gifts), event.Tier)
+	default:
+		return
+	}
+
+	if result.Skip {
+		return
+	}
+
+	logrus.Infof("generating tts from %s %s", source, event.Source)
+	message = strings.TrimSpace(html.UnescapeString(message))
+	alt := datastructures.SseEventTtsAlert{}
+	image, audio, volume := result.Alert.Parse()
+	if result.Volume != 0 {
+		volume = result.Volume
+	}
+	alt.Audio = audio
+	alt.Image = image
+	alt.Text = alertText
+	alt.SubText = strings.TrimSpace(html.UnescapeString(subText))
+	alt.Type = result.Alert.Type
+	alt.Volume = volume
+
+	req := datastructures.TtsRequest{
+		ID:            primitive.NewObjectIDFromTimestamp(time.Now()),
+		ChannelID:     event.ChannelID,
+		Text:          message,
+		Voice:         d.voices.Pick(event.ChannelID, alertType),
+		ValidVoices:   result.Voices,
+		Markup:        result.Markup,
+		Sounds:        result.Sounds,
+		MaxVoiceSwaps: 5,
+		Alert:         &alt,
+		Trigger:       event.AudioTrigger,
+	}
+
+	if _, err := d.gCtx.Inst().Queue.Enqueue(d.gCtx, req); err != nil {
+		logrus.WithError(err).Error("failed to queue tts")
+	}
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"strconv"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event is a normalized trigger emitted by a Source.
+type Event struct {
+	datastructures.AudioTrigger
+	// ChannelID is the channel the event belongs to.
+	ChannelID primitive.ObjectID
+	Message   string
+	// Login is the twitch login of the user, every source reports the same login so a gift can be matched across sources.
+	Login string
+	// SenderCount is the total amount of subs the gifter has gifted to the channel.
+	SenderCount int
+}
+
+// Source is a provider of events such as StreamElements or Twitch.
+type Source interface {
+	Name() string
+	Events() <-chan Event
+}
+
+// Tier normalizes a sub tier to "1000", "2000" or "3000", prime and missing tiers are tier 1.
+func Tier(v interface{}) string {
+	switch t := v.(type) {
+	case float64:
+		v = int(t)
+	case int:
+	case string:
+		if n, err := strconv.Atoi(t); err == nil {
+			v = n
+		}
+	}
+
+	switch v {
+	case 2, 2000, "2", "2000":
+		return "2000"
+	case 3, 3000, "3", "3000":
+		return "3000"
+	}
+	return "1000"
+}
@@ -0,0 +1,169 @@
+package exchange
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/gobuffalo/packr/v2"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+var (
+	ErrUnknownCurrency = fmt.Errorf("unknown currency")
+	ErrInvalidRates    = fmt.Errorf("invalid rates file")
+)
+
+// Table holds how many units of every currency one unit of the base currency is worth.
+type Table struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+var defaultTable Table
+
+func init() {
+	box := packr.New("exchange-static", "./static")
+	data, err := box.Find("rates.json")
+	if err != nil {
+		panic(err)
+	}
+
+	if err := json.Unmarshal(data, &defaultTable); err != nil {
+		panic(err)
+	}
+}
+
+// Convert converts an amount between two currencies of the table.
+func (t Table) Convert(amount float64, from, to string) (float64, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, ok := t.Rates[from]
+	if !ok || fromRate <= 0 {
+		return 0, ErrUnknownCurrency
+	}
+	toRate, ok := t.Rates[to]
+	if !ok || toRate <= 0 {
+		return 0, ErrUnknownCurrency
+	}
+
+	return amount / fromRate * toRate, nil
+}
+
+type Rates struct {
+	gCtx  global.Context
+	mtx   sync.RWMutex
+	table Table
+}
+
+func New(ctx global.Context) (*Rates, error) {
+	r := &Rates{
+		gCtx: ctx,
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.table.Convert(1, r.Base(), r.table.Base); err != nil {
+		return nil, fmt.Errorf("base currency %s: %w", r.Base(), err)
+	}
+
+	interval := time.Duration(ctx.Config().Currency.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				if err := r.Reload(); err != nil {
+					logrus.WithError(err).Error("failed to reload exchange rates")
+				}
+			}
+		}
+	}()
+
+	return r, nil
+}
+
+// Reload reads the rates file when one is configured, otherwise the bundled rates are used.
+// Rates set in the config always take precedence.
+func (r *Rates) Reload() error {
+	table := Table{
+		Base:  defaultTable.Base,
+		Rates: map[string]float64{},
+	}
+	for k, v := range defaultTable.Rates {
+		table.Rates[k] = v
+	}
+
+	cfg := r.gCtx.Config().Currency
+	if cfg.RatesFile != "" {
+		data, err := os.ReadFile(cfg.RatesFile)
+		if err != nil {
+			return err
+		}
+		table = Table{}
+		if err = json.Unmarshal(data, &table); err != nil {
+			return err
+		}
+		// a broken file keeps the rates which are loaded already.
+		if table.Base == "" || table.Rates == nil {
+			return fmt.Errorf("%w: %s needs a base and rates", ErrInvalidRates, cfg.RatesFile)
+		}
+		for k, v := range table.Rates {
+			if v <= 0 {
+				return fmt.Errorf("%w: rate of %s is %v", ErrInvalidRates, k, v)
+			}
+		}
+	}
+
+	table.Base = strings.ToUpper(table.Base)
+	for k, v := range cfg.Rates {
+		table.Rates[strings.ToUpper(k)] = v
+	}
+	table.Rates[table.Base] = 1
+
+	r.mtx.Lock()
+	r.table = table
+	r.mtx.Unlock()
+
+	return nil
+}
+
+// ToBase converts an amount into the configured base currency.
+func (r *Rates) ToBase(amount float64, currency string) (float64, error) {
+	if currency == "" {
+		return amount, nil
+	}
+
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	return r.table.Convert(amount, currency, r.Base())
+}
+
+// Base is the configured base currency, defaulting to euros.
+func (r *Rates) Base() string {
+	if base := r.gCtx.Config().Currency.Base; base != "" {
+		return strings.ToUpper(base)
+	}
+
+	return "EUR"
+}
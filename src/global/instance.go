@@ -3,7 +3,14 @@ package global
 import instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
 
 type Instance struct {
-	Mongo instance.Mongo
-	Redis instance.Redis
-	TTS   instance.TTS
+	Mongo          instance.Mongo
+	Redis          instance.Redis
+	TTS            instance.TTS
+	Queue          instance.Queue
+	Channels       instance.Channels
+	DeadLetters    instance.DeadLetters
+	Blacklist      instance.Blacklist
+	Pronunciations instance.Pronunciations
+	Cache          instance.Cache
+	Workers        instance.Workers
 }
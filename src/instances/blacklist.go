@@ -0,0 +1,16 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/blacklist"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Blacklist interface {
+	For(channelID primitive.ObjectID) blacklist.List
+	Entries(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.BlacklistEntry, error)
+	Add(ctx context.Context, entry datastructures.BlacklistEntry) (datastructures.BlacklistEntry, error)
+	Remove(ctx context.Context, id primitive.ObjectID) error
+}
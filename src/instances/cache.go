@@ -0,0 +1,14 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+)
+
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Put(ctx context.Context, key string, data []byte)
+	Stats(ctx context.Context) (datastructures.CacheStats, error)
+	Clear(ctx context.Context) error
+}
@@ -0,0 +1,13 @@
+package instance
+
+import (
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Channels interface {
+	All() []datastructures.Channel
+	Get(id primitive.ObjectID) (datastructures.Channel, bool)
+	ByLogin(login string) (datastructures.Channel, bool)
+	Watch(ch chan struct{})
+}
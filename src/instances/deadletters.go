@@ -0,0 +1,16 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DeadLetters interface {
+	Capture(ctx context.Context, req datastructures.TtsRequest, err error) error
+	Resolve(ctx context.Context, id primitive.ObjectID) error
+	List(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.DeadLetter, error)
+	Retry(ctx context.Context, id primitive.ObjectID) error
+	Discard(ctx context.Context, id primitive.ObjectID) error
+}
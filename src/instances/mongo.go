@@ -2,6 +2,7 @@ package instance
 
 import (
 	"context"
+	"time"
 
 	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,4 +12,22 @@ type Mongo interface {
 	Ping(ctx context.Context) error
 	FetchOverlay(ctx context.Context, token primitive.ObjectID) (datastructures.Overlay, error)
 	FetchVoices(ctx context.Context) ([]datastructures.AudioConfig, error)
+	FetchAlertRules(ctx context.Context) ([]datastructures.AlertRules, error)
+	SaveAudio(ctx context.Context, audio datastructures.Audio) error
+	DeleteAudio(ctx context.Context, id primitive.ObjectID) error
+	FetchChannels(ctx context.Context) ([]datastructures.Channel, error)
+	FetchDeadLetter(ctx context.Context, id primitive.ObjectID) (datastructures.DeadLetter, error)
+	FetchDeadLetters(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.DeadLetter, error)
+	FetchDueDeadLetters(ctx context.Context, now time.Time) ([]datastructures.DeadLetter, error)
+	SaveDeadLetter(ctx context.Context, letter datastructures.DeadLetter) error
+	SwapDeadLetter(ctx context.Context, old datastructures.DeadLetter, letter datastructures.DeadLetter) (bool, error)
+	DeleteDeadLetter(ctx context.Context, id primitive.ObjectID) error
+	FetchBlacklist(ctx context.Context) ([]datastructures.BlacklistEntry, error)
+	FetchBlacklistEntry(ctx context.Context, id primitive.ObjectID) (datastructures.BlacklistEntry, error)
+	InsertBlacklistEntry(ctx context.Context, entry datastructures.BlacklistEntry) error
+	DeleteBlacklistEntry(ctx context.Context, id primitive.ObjectID) error
+	FetchPronunciations(ctx context.Context) ([]datastructures.Pronunciation, error)
+	FetchPronunciation(ctx context.Context, id primitive.ObjectID) (datastructures.Pronunciation, error)
+	InsertPronunciations(ctx context.Context, entries []datastructures.Pronunciation) error
+	DeletePronunciation(ctx context.Context, id primitive.ObjectID) error
 }
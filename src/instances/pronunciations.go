@@ -0,0 +1,16 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/dictionary"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Pronunciations interface {
+	For(channelID primitive.ObjectID) *dictionary.Dictionary
+	Entries(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.Pronunciation, error)
+	Add(ctx context.Context, entries ...datastructures.Pronunciation) ([]datastructures.Pronunciation, error)
+	Remove(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,17 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Queue interface {
+	Enqueue(ctx context.Context, req datastructures.TtsRequest) (<-chan error, error)
+	Held(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.TtsRequest, error)
+	Approve(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID) error
+	Reject(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID) error
+	Edit(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID, text string) error
+	Skip(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID) error
+}
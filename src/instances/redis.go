@@ -10,6 +10,36 @@ type Redis interface {
 	Subscribe(ctx context.Context, ch chan string, subscribeTo ...string)
 	Publish(ctx context.Context, channel string, data string) error
 	SAdd(ctx context.Context, set string, values ...interface{}) error
+	SRem(ctx context.Context, set string, values ...interface{}) error
 	Set(ctx context.Context, key string, value string, expiry time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
+	SetNX(ctx context.Context, key string, value string, expiry time.Duration) (bool, error)
+	SMembers(ctx context.Context, set string) ([]string, error)
+	RPush(ctx context.Context, list string, values ...interface{}) error
+	LRange(ctx context.Context, list string, start, stop int64) ([]string, error)
+	LRem(ctx context.Context, list string, count int64, value interface{}) (int64, error)
+	HSet(ctx context.Context, hash string, field string, value string) error
+	HGet(ctx context.Context, hash string, field string) (string, error)
+	HDel(ctx context.Context, hash string, fields ...string) error
+	Multi(ctx context.Context, cmds ...[]interface{}) error
+	HIncrBy(ctx context.Context, hash string, field string, incr int64) (int64, error)
+	HGetAll(ctx context.Context, hash string) (map[string]string, error)
+	Del(ctx context.Context, keys ...string) (int64, error)
+	Expire(ctx context.Context, key string, expiry time.Duration) error
+	StrLen(ctx context.Context, key string) (int64, error)
+	ZAdd(ctx context.Context, set string, score float64, member string) error
+	ZCard(ctx context.Context, set string) (int64, error)
+	ZPopMin(ctx context.Context, set string, count int64) ([]string, error)
+	XAdd(ctx context.Context, stream string, values map[string]interface{}) (string, error)
+	XDel(ctx context.Context, stream string, ids ...string) error
+	XGroupCreate(ctx context.Context, stream string, group string) error
+	XReadGroup(ctx context.Context, stream string, group string, consumer string, count int64, block time.Duration) ([]StreamMessage, error)
+	XAck(ctx context.Context, stream string, group string, ids ...string) error
+	XAutoClaim(ctx context.Context, stream string, group string, consumer string, minIdle time.Duration, start string, count int64) ([]StreamMessage, string, error)
+}
+
+// StreamMessage is an entry of a redis stream.
+type StreamMessage struct {
+	ID     string
+	Values map[string]interface{}
 }
@@ -0,0 +1,13 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+)
+
+type Workers interface {
+	All() []datastructures.Worker
+	Available(req datastructures.WorkerRequirements) bool
+	Stream(ctx context.Context, req datastructures.WorkerRequirements) (string, error)
+}
@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/events"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/streamelements"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type seSource struct {
+	source streamelements.Source
+	name   string
+	auth   datastructures.ChannelStreamElements
+}
+
+// seSources runs a StreamElements source for every channel which has it enabled.
+type seSources struct {
+	gCtx       global.Context
+	dispatcher *events.Dispatcher
+	sources    map[primitive.ObjectID]seSource
+}
+
+// runStreamElements starts the StreamElements sources and follows the channels when they are reloaded,
+// sources are started for new channels, restarted when their token changes and stopped for removed channels.
+func runStreamElements(ctx global.Context, dispatcher *events.Dispatcher) {
+	s := &seSources{
+		gCtx:       ctx,
+		dispatcher: dispatcher,
+		sources:    map[primitive.ObjectID]seSource{},
+	}
+	s.sync()
+
+	ch := make(chan struct{}, 1)
+	ctx.Inst().Channels.Watch(ch)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				s.sync()
+			}
+		}
+	}()
+}
+
+func (s *seSources) sync() {
+	channels := map[primitive.ObjectID]datastructures.Channel{}
+	for _, channel := range s.gCtx.Inst().Channels.All() {
+		if channel.StreamElements.Enabled {
+			channels[channel.ID] = channel
+		}
+	}
+
+	for id, v := range s.sources {
+		if channel, ok := channels[id]; ok && channel.StreamElements == v.auth {
+			continue
+		}
+		if err := v.source.Close(); err != nil {
+			logrus.WithError(err).WithField("channel", v.name).Warn("failed to close streamelements")
+		}
+		delete(s.sources, id)
+		logrus.WithField("channel", v.name).Info("streamelements stopped")
+	}
+
+	for id, channel := range channels {
+		if _, ok := s.sources[id]; ok {
+			continue
+		}
+		// a channel which fails is tried again on the next reload.
+		source, err := streamelements.NewSource(s.gCtx, channel)
+		if err != nil {
+			logrus.WithError(err).WithField("channel", channel.Name).Error("streamelements failed")
+			continue
+		}
+		s.sources[id] = seSource{
+			source: source,
+			name:   channel.Name,
+			auth:   channel.StreamElements,
+		}
+		s.dispatcher.Run(source)
+		logrus.WithField("channel", channel.Name).Info("streamelements started")
+	}
+}
@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"time"
 
 	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
 	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
@@ -39,6 +40,159 @@ func (i *mongoInstance) FetchVoices(ctx context.Context) ([]datastructures.Audio
 	return vcs, err
 }
 
+func (i *mongoInstance) FetchAlertRules(ctx context.Context) ([]datastructures.AlertRules, error) {
+	rules := []datastructures.AlertRules{}
+	cur, err := i.db.Collection("alert_rules").Find(ctx, bson.M{})
+	if err == nil {
+		err = cur.All(ctx, &rules)
+	}
+	return rules, err
+}
+
+func (i *mongoInstance) SaveAudio(ctx context.Context, audio datastructures.Audio) error {
+	_, err := i.db.Collection("audio").ReplaceOne(ctx, bson.M{"_id": audio.ID}, audio, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (i *mongoInstance) DeleteAudio(ctx context.Context, id primitive.ObjectID) error {
+	_, err := i.db.Collection("audio").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (i *mongoInstance) FetchChannels(ctx context.Context) ([]datastructures.Channel, error) {
+	channels := []datastructures.Channel{}
+	cur, err := i.db.Collection("channels").Find(ctx, bson.M{})
+	if err == nil {
+		err = cur.All(ctx, &channels)
+	}
+	return channels, err
+}
+
+func (i *mongoInstance) FetchDeadLetter(ctx context.Context, id primitive.ObjectID) (datastructures.DeadLetter, error) {
+	letter := datastructures.DeadLetter{}
+	res := i.db.Collection("dead_letters").FindOne(ctx, bson.M{"_id": id})
+	err := res.Err()
+	if err == nil {
+		err = res.Decode(&letter)
+	}
+	return letter, err
+}
+
+func (i *mongoInstance) FetchDeadLetters(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.DeadLetter, error) {
+	filter := bson.M{}
+	if !channelID.IsZero() {
+		filter["channel_id"] = channelID
+	}
+
+	letters := []datastructures.DeadLetter{}
+	cur, err := i.db.Collection("dead_letters").Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err == nil {
+		err = cur.All(ctx, &letters)
+	}
+	return letters, err
+}
+
+func (i *mongoInstance) FetchDueDeadLetters(ctx context.Context, now time.Time) ([]datastructures.DeadLetter, error) {
+	letters := []datastructures.DeadLetter{}
+	cur, err := i.db.Collection("dead_letters").Find(ctx, bson.M{
+		"state":        datastructures.DeadLetterStatePending,
+		"next_attempt": bson.M{"$lte": now},
+	}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err == nil {
+		err = cur.All(ctx, &letters)
+	}
+	return letters, err
+}
+
+func (i *mongoInstance) SaveDeadLetter(ctx context.Context, letter datastructures.DeadLetter) error {
+	_, err := i.db.Collection("dead_letters").ReplaceOne(ctx, bson.M{"_id": letter.ID}, letter, options.Replace().SetUpsert(true))
+	return err
+}
+
+// SwapDeadLetter replaces a letter unless it has been changed since old was read.
+func (i *mongoInstance) SwapDeadLetter(ctx context.Context, old datastructures.DeadLetter, letter datastructures.DeadLetter) (bool, error) {
+	res, err := i.db.Collection("dead_letters").ReplaceOne(ctx, bson.M{
+		"_id":        old.ID,
+		"state":      old.State,
+		"updated_at": old.UpdatedAt,
+	}, letter)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (i *mongoInstance) DeleteDeadLetter(ctx context.Context, id primitive.ObjectID) error {
+	_, err := i.db.Collection("dead_letters").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (i *mongoInstance) FetchBlacklist(ctx context.Context) ([]datastructures.BlacklistEntry, error) {
+	entries := []datastructures.BlacklistEntry{}
+	cur, err := i.db.Collection("blacklist").Find(ctx, bson.M{})
+	if err == nil {
+		err = cur.All(ctx, &entries)
+	}
+	return entries, err
+}
+
+func (i *mongoInstance) FetchBlacklistEntry(ctx context.Context, id primitive.ObjectID) (datastructures.BlacklistEntry, error) {
+	entry := datastructures.BlacklistEntry{}
+	res := i.db.Collection("blacklist").FindOne(ctx, bson.M{"_id": id})
+	err := res.Err()
+	if err == nil {
+		err = res.Decode(&entry)
+	}
+	return entry, err
+}
+
+func (i *mongoInstance) InsertBlacklistEntry(ctx context.Context, entry datastructures.BlacklistEntry) error {
+	_, err := i.db.Collection("blacklist").InsertOne(ctx, entry)
+	return err
+}
+
+func (i *mongoInstance) DeleteBlacklistEntry(ctx context.Context, id primitive.ObjectID) error {
+	_, err := i.db.Collection("blacklist").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (i *mongoInstance) FetchPronunciations(ctx context.Context) ([]datastructures.Pronunciation, error) {
+	entries := []datastructures.Pronunciation{}
+	cur, err := i.db.Collection("pronunciations").Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err == nil {
+		err = cur.All(ctx, &entries)
+	}
+	return entries, err
+}
+
+func (i *mongoInstance) FetchPronunciation(ctx context.Context, id primitive.ObjectID) (datastructures.Pronunciation, error) {
+	entry := datastructures.Pronunciation{}
+	res := i.db.Collection("pronunciations").FindOne(ctx, bson.M{"_id": id})
+	err := res.Err()
+	if err == nil {
+		err = res.Decode(&entry)
+	}
+	return entry, err
+}
+
+func (i *mongoInstance) InsertPronunciations(ctx context.Context, entries []datastructures.Pronunciation) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(entries))
+	for idx, e := range entries {
+		docs[idx] = e
+	}
+	_, err := i.db.Collection("pronunciations").InsertMany(ctx, docs)
+	return err
+}
+
+func (i *mongoInstance) DeletePronunciation(ctx context.Context, id primitive.ObjectID) error {
+	_, err := i.db.Collection("pronunciations").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
 func NewInstance(ctx context.Context, uri, db string) (instance.Mongo, error) {
 	c, err := mongo.NewClient(options.Client().ApplyURI(uri))
 	if err != nil {
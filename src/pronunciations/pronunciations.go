@@ -0,0 +1,161 @@
+package pronunciations
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/dictionary"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reloadEvent is published after the dictionary changed so every controller reloads it.
+const reloadEvent = "events:pronunciations:reload"
+
+type pronunciationsInstance struct {
+	gCtx     global.Context
+	mtx      sync.RWMutex
+	global   *dictionary.Dictionary
+	channels map[primitive.ObjectID]*dictionary.Dictionary
+}
+
+// NewInstance loads the pronunciations from mongo on top of the built in dictionary and keeps them up to date.
+func NewInstance(ctx global.Context) (instance.Pronunciations, error) {
+	inst := &pronunciationsInstance{
+		gCtx: ctx,
+	}
+
+	if err := inst.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan string)
+	ctx.Inst().Redis.Subscribe(ctx, ch, reloadEvent)
+
+	go func() {
+		tick := time.NewTicker(time.Minute)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+			case <-ch:
+			}
+			if err := inst.reload(ctx); err != nil {
+				logrus.WithError(err).Error("failed to reload pronunciations")
+			}
+		}
+	}()
+
+	return inst, nil
+}
+
+func (inst *pronunciationsInstance) reload(ctx context.Context) error {
+	entries, err := inst.gCtx.Inst().Mongo.FetchPronunciations(ctx)
+	if err != nil {
+		return err
+	}
+
+	global := append([]datastructures.Pronunciation{}, dictionary.Defaults...)
+	channels := map[primitive.ObjectID][]datastructures.Pronunciation{}
+	for _, e := range entries {
+		// a single bad entry should not disable the whole dictionary.
+		if err := dictionary.Validate(e); err != nil {
+			logrus.WithError(err).WithField("id", e.ID.Hex()).Error("bad pronunciation")
+			continue
+		}
+		if e.ChannelID.IsZero() {
+			global = append(global, e)
+		} else {
+			channels[e.ChannelID] = append(channels[e.ChannelID], e)
+		}
+	}
+
+	globalDict, err := dictionary.Compile(global)
+	if err != nil {
+		return err
+	}
+	channelDicts := make(map[primitive.ObjectID]*dictionary.Dictionary, len(channels))
+	for id, v := range channels {
+		// channel entries come last so they win over the global ones.
+		if channelDicts[id], err = dictionary.Compile(append(append([]datastructures.Pronunciation{}, global...), v...)); err != nil {
+			return err
+		}
+	}
+
+	inst.mtx.Lock()
+	inst.global = globalDict
+	inst.channels = channelDicts
+	inst.mtx.Unlock()
+
+	return nil
+}
+
+func (inst *pronunciationsInstance) For(channelID primitive.ObjectID) *dictionary.Dictionary {
+	inst.mtx.RLock()
+	defer inst.mtx.RUnlock()
+
+	if d, ok := inst.channels[channelID]; ok {
+		return d
+	}
+	return inst.global
+}
+
+// Entries returns the stored entries of a channel, the global entries are returned for a zero channel id.
+func (inst *pronunciationsInstance) Entries(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.Pronunciation, error) {
+	entries, err := inst.gCtx.Inst().Mongo.FetchPronunciations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []datastructures.Pronunciation{}
+	for _, e := range entries {
+		if e.ChannelID == channelID {
+			out = append(out, e)
+		}
+	}
+
+	return out, nil
+}
+
+// Add stores entries when all of them are valid, so an import is either stored as a whole or not at all.
+func (inst *pronunciationsInstance) Add(ctx context.Context, entries ...datastructures.Pronunciation) ([]datastructures.Pronunciation, error) {
+	for _, e := range entries {
+		if err := dictionary.Validate(e); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for i := range entries {
+		entries[i].ID = primitive.NewObjectIDFromTimestamp(now)
+		entries[i].CreatedAt = now
+	}
+	if err := inst.gCtx.Inst().Mongo.InsertPronunciations(ctx, entries); err != nil {
+		return nil, err
+	}
+
+	return entries, inst.changed(ctx)
+}
+
+func (inst *pronunciationsInstance) Remove(ctx context.Context, id primitive.ObjectID) error {
+	if err := inst.gCtx.Inst().Mongo.DeletePronunciation(ctx, id); err != nil {
+		return err
+	}
+
+	return inst.changed(ctx)
+}
+
+// changed reloads the dictionary and tells the other controllers to do the same.
+func (inst *pronunciationsInstance) changed(ctx context.Context) error {
+	if err := inst.reload(ctx); err != nil {
+		return err
+	}
+
+	return inst.gCtx.Inst().Redis.Publish(ctx, reloadEvent, "1")
+}
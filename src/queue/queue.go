@@ -0,0 +1,615 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser"
+	"github.com/go-redis/redis/v8"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+const (
+	ItemStateSynthesizing = "SYNTHESIZING"
+	ItemStateReady        = "READY"
+	ItemStateFailed       = "FAILED"
+)
+
+var (
+	ErrNotFound = fmt.Errorf("item not found")
+	ErrNotHeld  = fmt.Errorf("item is not held")
+)
+
+const channelsKey = "tts:queue:channels"
+
+// leaseTTL is how long a controller owns the synthesis of an item without renewing its lease,
+// afterwards another controller synthesizes the item again.
+const leaseTTL = time.Second * 30
+
+// Item is a request waiting in the queue of its channel.
+type Item struct {
+	Request datastructures.TtsRequest `json:"request"`
+	State   string                    `json:"state"`
+	// Held items are synthesized right away but only released once a moderator approved them or the hold expired.
+	Held      bool      `json:"held,omitempty"`
+	HeldUntil time.Time `json:"held_until,omitempty"`
+	// Owner is the lease of the synthesis which may store its result, other syntheses of the item are stale.
+	Owner string `json:"owner,omitempty"`
+}
+
+type queueInstance struct {
+	gCtx     global.Context
+	mtx      sync.Mutex
+	channels map[primitive.ObjectID]chan struct{}
+	// itemMtx guards the state changes of held items.
+	itemMtx sync.Mutex
+	// running are the syntheses of this controller which can still be cancelled.
+	runningMtx sync.Mutex
+	running    map[primitive.ObjectID]*synthesis
+}
+
+// NewInstance creates the queue and resumes every queue left over from a previous run. Items are synthesized
+// in parallel by the controller which owns their lease, but released to the overlay strictly in the order they were queued.
+func NewInstance(ctx global.Context) (instance.Queue, error) {
+	inst := &queueInstance{
+		gCtx:     ctx,
+		channels: map[primitive.ObjectID]chan struct{}{},
+		running:  map[primitive.ObjectID]*synthesis{},
+	}
+
+	channels, err := ctx.Inst().Redis.SMembers(ctx, channelsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range channels {
+		channelID, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			continue
+		}
+
+		if err := inst.resume(ctx, channelID); err != nil {
+			return nil, err
+		}
+
+		inst.notify(channelID)
+	}
+
+	return inst, nil
+}
+
+func listKey(channelID primitive.ObjectID) string {
+	return fmt.Sprintf("tts:queue:%s", channelID.Hex())
+}
+
+func itemsKey(channelID primitive.ObjectID) string {
+	return fmt.Sprintf("tts:queue:%s:items", channelID.Hex())
+}
+
+func leaseKey(id primitive.ObjectID) string {
+	return fmt.Sprintf("tts:queue:lease:%s", id.Hex())
+}
+
+func (inst *queueInstance) Enqueue(ctx context.Context, req datastructures.TtsRequest) (<-chan error, error) {
+	item := Item{
+		Request: req,
+		State:   ItemStateSynthesizing,
+	}
+	if hold, ok := inst.hold(req); ok {
+		item.Held = true
+		item.HeldUntil = time.Now().Add(time.Duration(hold.Timeout) * time.Second)
+	}
+	if _, err := inst.claim(ctx, &item); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalToString(item)
+	if err != nil {
+		return nil, err
+	}
+	// a controller which reads the queue never sees a half queued item.
+	if err := inst.gCtx.Inst().Redis.Multi(ctx,
+		[]interface{}{"HSET", itemsKey(req.ChannelID), req.ID.Hex(), data},
+		[]interface{}{"RPUSH", listKey(req.ChannelID), req.ID.Hex()},
+		[]interface{}{"SADD", channelsKey, req.ChannelID.Hex()},
+	); err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	inst.start(item, done)
+
+	return done, nil
+}
+
+func (inst *queueInstance) item(ctx context.Context, channelID primitive.ObjectID, id string) (Item, error) {
+	item := Item{}
+	data, err := inst.gCtx.Inst().Redis.HGet(ctx, itemsKey(channelID), id)
+	if err == nil {
+		err = json.UnmarshalFromString(data, &item)
+	}
+	return item, err
+}
+
+func (inst *queueInstance) save(ctx context.Context, item Item) error {
+	data, err := json.MarshalToString(item)
+	if err != nil {
+		return err
+	}
+
+	return inst.gCtx.Inst().Redis.HSet(ctx, itemsKey(item.Request.ChannelID), item.Request.ID.Hex(), data)
+}
+
+// claim takes the lease of an item, it fails while another synthesis of the item owns the lease.
+func (inst *queueInstance) claim(ctx context.Context, item *Item) (bool, error) {
+	owner := primitive.NewObjectID().Hex()
+	ok, err := inst.gCtx.Inst().Redis.SetNX(ctx, leaseKey(item.Request.ID), owner, leaseTTL)
+	if ok {
+		item.Owner = owner
+	}
+	return ok, err
+}
+
+// resume synthesizes the items of a channel whose lease expired, the controller which synthesized them is gone.
+func (inst *queueInstance) resume(ctx context.Context, channelID primitive.ObjectID) error {
+	r := inst.gCtx.Inst().Redis
+	ids, err := r.LRange(ctx, listKey(channelID), 0, -1)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		item, err := inst.item(ctx, channelID, id)
+		if err != nil {
+			if err == redis.Nil {
+				_, _ = r.LRem(ctx, listKey(channelID), 0, id)
+				continue
+			}
+			return err
+		}
+		if item.State != ItemStateSynthesizing {
+			continue
+		}
+
+		owner := item.Owner
+		if ok, err := inst.claim(ctx, &item); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		inst.itemMtx.Lock()
+		// the item might have changed before the lease was taken.
+		cur, err := inst.item(ctx, channelID, id)
+		if err == nil && cur.State == ItemStateSynthesizing && cur.Owner == owner {
+			cur.Owner = item.Owner
+			if err = inst.save(ctx, cur); err == nil {
+				logrus.WithField("id", id).Info("resuming queue item")
+				inst.start(cur, nil)
+			}
+		} else {
+			_, _ = r.Del(ctx, leaseKey(item.Request.ID))
+		}
+		inst.itemMtx.Unlock()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// synthesis is a running synthesis of an item.
+type synthesis struct {
+	cancel context.CancelFunc
+}
+
+// start synthesizes an item in the background, the synthesis can be stopped with cancel.
+// The lease of the item is renewed while it runs, the synthesis stops once it lost the lease.
+func (inst *queueInstance) start(item Item, done chan error) {
+	ctx, cancel := context.WithCancel(inst.gCtx)
+	s := &synthesis{cancel: cancel}
+
+	inst.runningMtx.Lock()
+	if old, ok := inst.running[item.Request.ID]; ok {
+		old.cancel()
+	}
+	inst.running[item.Request.ID] = s
+	inst.runningMtx.Unlock()
+
+	go inst.renew(ctx, item, cancel)
+	go func() {
+		inst.synthesize(ctx, item, done)
+
+		inst.runningMtx.Lock()
+		// the item might have been started again in the meantime.
+		if inst.running[item.Request.ID] == s {
+			delete(inst.running, item.Request.ID)
+		}
+		inst.runningMtx.Unlock()
+		cancel()
+	}()
+}
+
+// renew keeps the lease of a running synthesis.
+func (inst *queueInstance) renew(ctx context.Context, item Item, cancel context.CancelFunc) {
+	r := inst.gCtx.Inst().Redis
+	key := leaseKey(item.Request.ID)
+
+	tick := time.NewTicker(leaseTTL / 3)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+
+		owner, err := r.Get(ctx, key)
+		if err != nil && err != redis.Nil {
+			logrus.WithError(err).Error("failed to read queue lease")
+			continue
+		}
+		if owner != item.Owner {
+			// the item was changed or resumed by another controller.
+			cancel()
+			return
+		}
+		if err := r.Set(ctx, key, owner, leaseTTL); err != nil {
+			logrus.WithError(err).Error("failed to renew queue lease")
+		}
+	}
+}
+
+// cancel stops the synthesis of an item when it is running, which withdraws its jobs from the workers.
+// It has to be called with itemMtx held, so the synthesis cannot store its result afterwards.
+func (inst *queueInstance) cancel(id primitive.ObjectID) {
+	inst.runningMtx.Lock()
+	defer inst.runningMtx.Unlock()
+
+	if s, ok := inst.running[id]; ok {
+		s.cancel()
+		delete(inst.running, id)
+	}
+}
+
+func (inst *queueInstance) synthesize(ctx context.Context, item Item, done chan error) {
+	deadLetters := inst.gCtx.Inst().DeadLetters
+
+	state := ItemStateReady
+	err := inst.gCtx.Inst().TTS.Generate(ctx, item.Request)
+	if err != nil {
+		switch {
+		case textparser.IsRejected(err):
+			// retrying a rejected message cannot succeed.
+			if err := deadLetters.Resolve(inst.gCtx, item.Request.ID); err != nil {
+				logrus.WithError(err).Error("failed to resolve dead letter")
+			}
+		case ctx.Err() == nil:
+			logrus.WithError(err).Error("failed to generate tts")
+			if err := deadLetters.Capture(inst.gCtx, item.Request, err); err != nil {
+				logrus.WithError(err).Error("failed to store dead letter")
+			}
+		}
+		state = ItemStateFailed
+	} else {
+		logrus.Info("generated tts")
+		if err := deadLetters.Resolve(inst.gCtx, item.Request.ID); err != nil {
+			logrus.WithError(err).Error("failed to resolve dead letter")
+		}
+	}
+
+	inst.itemMtx.Lock()
+	if ctx.Err() != nil {
+		// the item was cancelled, whoever cancelled it already changed its state.
+		inst.itemMtx.Unlock()
+		if done != nil {
+			done <- ctx.Err()
+			close(done)
+		}
+		return
+	}
+	// a moderator might have approved the item in the meantime, only its state is ours to change.
+	cur, serr := inst.item(inst.gCtx, item.Request.ChannelID, item.Request.ID.Hex())
+	if serr == nil && cur.Owner == item.Owner {
+		cur.State = state
+		cur.Owner = ""
+		if serr = inst.save(inst.gCtx, cur); serr == nil {
+			_, serr = inst.gCtx.Inst().Redis.Del(inst.gCtx, leaseKey(item.Request.ID))
+		}
+	}
+	if serr != nil && serr != redis.Nil {
+		logrus.WithError(serr).Error("failed to update queue item")
+	}
+	inst.itemMtx.Unlock()
+
+	if done != nil {
+		done <- err
+		close(done)
+	}
+
+	inst.notify(item.Request.ChannelID)
+}
+
+// hold returns the hold settings of the channel when the request has to wait for a moderator.
+// Manual messages and alerts without a message are never held.
+func (inst *queueInstance) hold(req datastructures.TtsRequest) (datastructures.ChannelHold, bool) {
+	if req.Text == "" || req.Trigger.Source == datastructures.AudioTriggerSourceManual {
+		return datastructures.ChannelHold{}, false
+	}
+
+	channel, ok := inst.gCtx.Inst().Channels.Get(req.ChannelID)
+	if !ok || !channel.Hold.Enabled {
+		return datastructures.ChannelHold{}, false
+	}
+
+	hold := channel.Hold
+	if hold.Timeout <= 0 {
+		hold.Timeout = 120
+	}
+
+	return hold, true
+}
+
+func (inst *queueInstance) Held(ctx context.Context, channelID primitive.ObjectID) ([]datastructures.TtsRequest, error) {
+	ids, err := inst.gCtx.Inst().Redis.LRange(ctx, listKey(channelID), 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs := []datastructures.TtsRequest{}
+	for _, id := range ids {
+		item, err := inst.item(ctx, channelID, id)
+		if err != nil {
+			if err == redis.Nil {
+				continue
+			}
+			return nil, err
+		}
+		if item.Held {
+			reqs = append(reqs, item.Request)
+		}
+	}
+
+	return reqs, nil
+}
+
+// update changes a held item, the queue of the channel is woken up afterwards. With synthesizing items
+// which are not held but still being synthesized can be changed too. An item which fn sets to synthesizing
+// without an owner is synthesized again by this controller.
+func (inst *queueInstance) update(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID, synthesizing bool, fn func(item *Item) error) error {
+	inst.itemMtx.Lock()
+	defer inst.itemMtx.Unlock()
+
+	item, err := inst.item(ctx, channelID, id.Hex())
+	if err != nil {
+		if err == redis.Nil {
+			return ErrNotFound
+		}
+		return err
+	}
+	if !item.Held && !(item.State == ItemStateSynthesizing && synthesizing) {
+		return ErrNotHeld
+	}
+
+	if err := fn(&item); err != nil {
+		return err
+	}
+	restart := false
+	if item.State == ItemStateSynthesizing && item.Owner == "" {
+		if restart, err = inst.claim(ctx, &item); err != nil {
+			return err
+		}
+	}
+	if err := inst.save(ctx, item); err != nil {
+		return err
+	}
+
+	if restart {
+		inst.start(item, nil)
+	}
+	inst.notify(channelID)
+
+	return nil
+}
+
+// Skip drops an item which has not been released yet, its synthesis is cancelled.
+func (inst *queueInstance) Skip(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID) error {
+	inst.itemMtx.Lock()
+	defer inst.itemMtx.Unlock()
+
+	item, err := inst.item(ctx, channelID, id.Hex())
+	if err != nil {
+		if err == redis.Nil {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	inst.cancel(id)
+	item.State = ItemStateFailed
+	item.Owner = ""
+	if err := inst.save(ctx, item); err != nil {
+		return err
+	}
+	if _, err := inst.gCtx.Inst().Redis.Del(ctx, leaseKey(id)); err != nil {
+		return err
+	}
+	// a skipped retry is never captured again.
+	if err := inst.gCtx.Inst().DeadLetters.Resolve(ctx, id); err != nil {
+		return err
+	}
+
+	inst.notify(channelID)
+	return nil
+}
+
+// Approve releases a held item, its audio is kept and the item is released as soon as it is synthesized.
+func (inst *queueInstance) Approve(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID) error {
+	return inst.update(ctx, channelID, id, false, approve)
+}
+
+// Reject drops the message of a held item, messages which are still synthesized can be rejected too.
+func (inst *queueInstance) Reject(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID) error {
+	return inst.update(ctx, channelID, id, true, func(item *Item) error {
+		return inst.setMessage(ctx, item, "")
+	})
+}
+
+// Edit replaces the message of a held item and synthesizes the new message.
+func (inst *queueInstance) Edit(ctx context.Context, channelID primitive.ObjectID, id primitive.ObjectID, text string) error {
+	if text == "" {
+		return inst.Reject(ctx, channelID, id)
+	}
+
+	return inst.update(ctx, channelID, id, false, func(item *Item) error {
+		return inst.setMessage(ctx, item, text)
+	})
+}
+
+// setMessage replaces the message of an item and releases it, the synthesis of the old message is
+// cancelled and its audio dropped. The alert shows the new message too, without a message the item
+// only shows the alert.
+func (inst *queueInstance) setMessage(ctx context.Context, item *Item, text string) error {
+	inst.cancel(item.Request.ID)
+	// a synthesis on another controller stops once it sees its lease is gone.
+	if _, err := inst.gCtx.Inst().Redis.Del(ctx, leaseKey(item.Request.ID)); err != nil {
+		return err
+	}
+	item.Owner = ""
+	if err := inst.gCtx.Inst().TTS.Discard(ctx, item.Request.ID); err != nil {
+		return err
+	}
+	// a rejected retry is never captured again, an edited one is resolved or captured by its new synthesis.
+	if text == "" {
+		if err := inst.gCtx.Inst().DeadLetters.Resolve(ctx, item.Request.ID); err != nil {
+			return err
+		}
+	}
+
+	if alert := item.Request.Alert; alert != nil {
+		alert.SubText = text
+	}
+	item.Request.Text = text
+	item.Held = false
+
+	switch {
+	case text != "":
+		item.State = ItemStateSynthesizing
+	case item.Request.Alert != nil:
+		item.State = ItemStateReady
+	default:
+		item.State = ItemStateFailed
+	}
+
+	return nil
+}
+
+// approve releases a held item.
+func approve(item *Item) error {
+	item.Held = false
+	return nil
+}
+
+// expire applies the timeout action of the channel once a held item has waited too long.
+func (inst *queueInstance) expire(ctx context.Context, item Item) error {
+	fn := approve
+	if channel, ok := inst.gCtx.Inst().Channels.Get(item.Request.ChannelID); ok && channel.Hold.Action == datastructures.ChannelHoldActionReject {
+		fn = func(item *Item) error {
+			return inst.setMessage(ctx, item, "")
+		}
+	}
+
+	err := inst.update(ctx, item.Request.ChannelID, item.Request.ID, false, fn)
+	if err == ErrNotHeld {
+		// a moderator got to it first.
+		return nil
+	}
+	return err
+}
+
+// notify wakes up the releaser of a channel, starting it if it is not running yet.
+func (inst *queueInstance) notify(channelID primitive.ObjectID) {
+	inst.mtx.Lock()
+	defer inst.mtx.Unlock()
+
+	ch, ok := inst.channels[channelID]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		inst.channels[channelID] = ch
+		go inst.release(channelID, ch)
+	}
+
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// release publishes the head of the queue of a channel as soon as it is ready,
+// a held head blocks the queue until a moderator acts on it or it expires.
+func (inst *queueInstance) release(channelID primitive.ObjectID, ch chan struct{}) {
+	ctx := inst.gCtx
+	r := ctx.Inst().Redis
+
+	tick := time.NewTicker(time.Second * 5)
+	defer tick.Stop()
+
+	for {
+		ids, err := r.LRange(ctx, listKey(channelID), 0, 0)
+		if err != nil {
+			logrus.WithError(err).Error("failed to read queue")
+		} else if len(ids) != 0 {
+			id := ids[0]
+			item, err := inst.item(ctx, channelID, id)
+			if err != nil && err != redis.Nil {
+				logrus.WithError(err).Error("failed to read queue item")
+			} else if err == nil && item.Held {
+				if time.Now().After(item.HeldUntil) {
+					if err := inst.expire(ctx, item); err != nil {
+						logrus.WithError(err).Error("failed to expire held item")
+					} else {
+						continue
+					}
+				}
+			} else if err == redis.Nil || item.State == ItemStateReady || item.State == ItemStateFailed {
+				// every controller releases every channel, only the one which pops the item publishes it.
+				popped, perr := r.LRem(ctx, listKey(channelID), 1, id)
+				if perr != nil {
+					logrus.WithError(perr).Error("failed to pop queue item")
+				} else {
+					if popped != 0 {
+						if err == nil && item.State == ItemStateReady {
+							if err := ctx.Inst().TTS.Publish(ctx, item.Request); err != nil {
+								logrus.WithError(err).Error("failed to publish tts")
+							}
+						}
+						if err := r.HDel(ctx, itemsKey(channelID), id); err != nil {
+							logrus.WithError(err).Error("failed to delete queue item")
+						}
+					}
+					continue
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+		case <-tick.C:
+			if err := inst.resume(ctx, channelID); err != nil {
+				logrus.WithError(err).Error("failed to resume queue")
+			}
+		}
+	}
+}
@@ -103,10 +103,100 @@ func (i *redisInstance) SAdd(ctx context.Context, key string, values ...interfac
 	return i.c.SAdd(ctx, key, values...).Err()
 }
 
+func (i *redisInstance) SRem(ctx context.Context, key string, values ...interface{}) error {
+	return i.c.SRem(ctx, key, values...).Err()
+}
+
 func (i *redisInstance) Set(ctx context.Context, key string, value string, expiry time.Duration) error {
 	return i.c.Set(ctx, key, value, expiry).Err()
 }
 
+func (i *redisInstance) SetNX(ctx context.Context, key string, value string, expiry time.Duration) (bool, error) {
+	return i.c.SetNX(ctx, key, value, expiry).Result()
+}
+
 func (i *redisInstance) Get(ctx context.Context, key string) (string, error) {
 	return i.c.Get(ctx, key).Result()
 }
+
+func (i *redisInstance) SMembers(ctx context.Context, set string) ([]string, error) {
+	return i.c.SMembers(ctx, set).Result()
+}
+
+func (i *redisInstance) RPush(ctx context.Context, list string, values ...interface{}) error {
+	return i.c.RPush(ctx, list, values...).Err()
+}
+
+func (i *redisInstance) LRange(ctx context.Context, list string, start, stop int64) ([]string, error) {
+	return i.c.LRange(ctx, list, start, stop).Result()
+}
+
+// LRem removes values from a list and returns how many were removed.
+func (i *redisInstance) LRem(ctx context.Context, list string, count int64, value interface{}) (int64, error) {
+	return i.c.LRem(ctx, list, count, value).Result()
+}
+
+func (i *redisInstance) HSet(ctx context.Context, hash string, field string, value string) error {
+	return i.c.HSet(ctx, hash, field, value).Err()
+}
+
+func (i *redisInstance) HGet(ctx context.Context, hash string, field string) (string, error) {
+	return i.c.HGet(ctx, hash, field).Result()
+}
+
+func (i *redisInstance) HDel(ctx context.Context, hash string, fields ...string) error {
+	return i.c.HDel(ctx, hash, fields...).Err()
+}
+
+func (i *redisInstance) HIncrBy(ctx context.Context, hash string, field string, incr int64) (int64, error) {
+	return i.c.HIncrBy(ctx, hash, field, incr).Result()
+}
+
+func (i *redisInstance) HGetAll(ctx context.Context, hash string) (map[string]string, error) {
+	return i.c.HGetAll(ctx, hash).Result()
+}
+
+func (i *redisInstance) Del(ctx context.Context, keys ...string) (int64, error) {
+	return i.c.Del(ctx, keys...).Result()
+}
+
+func (i *redisInstance) Expire(ctx context.Context, key string, expiry time.Duration) error {
+	return i.c.Expire(ctx, key, expiry).Err()
+}
+
+func (i *redisInstance) StrLen(ctx context.Context, key string) (int64, error) {
+	return i.c.StrLen(ctx, key).Result()
+}
+
+func (i *redisInstance) ZAdd(ctx context.Context, set string, score float64, member string) error {
+	return i.c.ZAdd(ctx, set, &redis.Z{Score: score, Member: member}).Err()
+}
+
+func (i *redisInstance) ZCard(ctx context.Context, set string) (int64, error) {
+	return i.c.ZCard(ctx, set).Result()
+}
+
+// ZPopMin removes and returns the members with the lowest scores.
+func (i *redisInstance) ZPopMin(ctx context.Context, set string, count int64) ([]string, error) {
+	zs, err := i.c.ZPopMin(ctx, set, count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, len(zs))
+	for j, z := range zs {
+		out[j], _ = z.Member.(string)
+	}
+	return out, nil
+}
+
+// Multi runs raw commands like {"HSET", key, field, value} in a single MULTI/EXEC transaction.
+func (i *redisInstance) Multi(ctx context.Context, cmds ...[]interface{}) error {
+	_, err := i.c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, cmd := range cmds {
+			pipe.Do(ctx, cmd...)
+		}
+		return nil
+	})
+	return err
+}
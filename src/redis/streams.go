@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	"github.com/go-redis/redis/v8"
+)
+
+func messages(msgs []redis.XMessage) []instance.StreamMessage {
+	out := make([]instance.StreamMessage, len(msgs))
+	for j, m := range msgs {
+		out[j] = instance.StreamMessage{ID: m.ID, Values: m.Values}
+	}
+	return out
+}
+
+// XAdd appends an entry to a stream and returns its id.
+func (i *redisInstance) XAdd(ctx context.Context, stream string, values map[string]interface{}) (string, error) {
+	return i.c.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}).Result()
+}
+
+func (i *redisInstance) XDel(ctx context.Context, stream string, ids ...string) error {
+	return i.c.XDel(ctx, stream, ids...).Err()
+}
+
+// XGroupCreate creates a consumer group which reads the stream from the start, the stream is created when it does not exist.
+// A group which already exists is left alone.
+func (i *redisInstance) XGroupCreate(ctx context.Context, stream string, group string) error {
+	err := i.c.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// XReadGroup reads new entries for a consumer of a group, it blocks until there are entries or block passed.
+func (i *redisInstance) XReadGroup(ctx context.Context, stream string, group string, consumer string, count int64, block time.Duration) ([]instance.StreamMessage, error) {
+	streams, err := i.c.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := []instance.StreamMessage{}
+	for _, s := range streams {
+		out = append(out, messages(s.Messages)...)
+	}
+	return out, nil
+}
+
+func (i *redisInstance) XAck(ctx context.Context, stream string, group string, ids ...string) error {
+	return i.c.XAck(ctx, stream, group, ids...).Err()
+}
+
+// XAutoClaim takes over the pending entries of a group which have been idle for at least minIdle,
+// it returns the claimed entries and the id to continue from, which is "0-0" once every entry has been seen.
+func (i *redisInstance) XAutoClaim(ctx context.Context, stream string, group string, consumer string, minIdle time.Duration, start string, count int64) ([]instance.StreamMessage, string, error) {
+	msgs, next, err := i.c.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return messages(msgs), next, nil
+}
@@ -0,0 +1,261 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/markup"
+	"github.com/gobuffalo/packr/v2"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+var defaultRules []datastructures.AlertRules
+
+func init() {
+	// packr resolves a box against the working directory first, a ./static folder here would shadow
+	// the static boxes of the textparser packages in the tests of this package.
+	box := packr.New("rules-defaults", "./defaults")
+	data, err := box.Find("rules.json")
+	if err != nil {
+		panic(err)
+	}
+
+	if err := json.Unmarshal(data, &defaultRules); err != nil {
+		panic(err)
+	}
+
+	if err := Validate(defaultRules); err != nil {
+		panic(err)
+	}
+}
+
+type Result struct {
+	Skip   bool
+	Alert  datastructures.AlertHelper
+	Volume int
+	Voices []string
+	Markup []string
+	Sounds []string
+}
+
+// Evaluate applies the rules of a single event type to an event.
+// amount is bits for cheers, the donated amount for donations, months for subscriptions
+// and the number of subs for gifts.
+func Evaluate(rules datastructures.AlertRules, amount float64, tier string) Result {
+	res := Result{
+		Alert: datastructures.AlertHelper{
+			Type: rules.Type,
+			Name: rules.Alert,
+		},
+	}
+	if res.Alert.Type == "" {
+		res.Alert.Type = rules.Event
+	}
+
+	if amount < rules.Minimum {
+		res.Skip = true
+		return res
+	}
+
+	voices := append([]string{}, rules.Voices...)
+	tags := append([]string{}, rules.Markup...)
+	sounds := append([]string{}, rules.Sounds...)
+	for _, t := range rules.Tiers {
+		if t.Tier != "" && t.Tier != tier {
+			continue
+		}
+
+		if t.Exact {
+			if math.Abs(amount-t.Amount) > 0.005 {
+				continue
+			}
+		} else if amount < t.Amount {
+			continue
+		}
+
+		if t.Alert != "" {
+			res.Alert.Name = t.Alert
+		}
+		if t.Volume != 0 {
+			res.Volume = t.Volume
+		}
+		voices = append(voices, t.Voices...)
+		tags = append(tags, t.Markup...)
+		sounds = append(sounds, t.Sounds...)
+	}
+
+	res.Voices = unique(voices)
+	res.Markup = unique(tags)
+	res.Sounds = unique(sounds)
+
+	return res
+}
+
+func unique(values []string) []string {
+	var ret []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		ret = append(ret, v)
+	}
+
+	return ret
+}
+
+// Validate makes sure every event is only defined once and only references voices, markup tags and sounds that exist.
+func Validate(rules []datastructures.AlertRules) error {
+	events := map[string]bool{}
+	for _, r := range rules {
+		switch r.Event {
+		case datastructures.AlertTypeCheer, datastructures.AlertTypeDonation, datastructures.AlertTypeSubscriber, datastructures.AlertTypeGift:
+		default:
+			return fmt.Errorf("unknown event %s", r.Event)
+		}
+		if events[r.Event] {
+			return fmt.Errorf("duplicate rules for event %s", r.Event)
+		}
+		events[r.Event] = true
+
+		voices := append([]string{}, r.Voices...)
+		for _, t := range r.Tiers {
+			voices = append(voices, t.Voices...)
+		}
+		for _, v := range voices {
+			if _, ok := textparser.VoicesMap[v]; !ok {
+				return fmt.Errorf("unknown voice %s in %s rules", v, r.Event)
+			}
+		}
+
+		tags := append([]string{}, r.Markup...)
+		for _, t := range r.Tiers {
+			tags = append(tags, t.Markup...)
+		}
+		for _, tag := range tags {
+			if !knownTag(tag) {
+				return fmt.Errorf("unknown markup tag %s in %s rules", tag, r.Event)
+			}
+		}
+
+		sounds := append([]string{}, r.Sounds...)
+		for _, t := range r.Tiers {
+			sounds = append(sounds, t.Sounds...)
+		}
+		for _, s := range sounds {
+			if _, ok := textparser.SoundsMap[s]; !ok {
+				return fmt.Errorf("unknown sound %s in %s rules", s, r.Event)
+			}
+		}
+	}
+
+	return nil
+}
+
+type Store struct {
+	gCtx  global.Context
+	mtx   sync.RWMutex
+	rules map[string]datastructures.AlertRules
+}
+
+func New(ctx global.Context) (*Store, error) {
+	s := &Store{
+		gCtx: ctx,
+	}
+
+	if err := s.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(ctx.Config().Rules.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				if err := s.Reload(ctx); err != nil {
+					logrus.WithError(err).Error("failed to reload alert rules")
+				}
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// Reload fetches the rules from the configured file, or from mongo when no file is set.
+// When mongo has no rules the bundled defaults are used.
+func (s *Store) Reload(ctx context.Context) error {
+	var rules []datastructures.AlertRules
+	if file := s.gCtx.Config().Rules.File; file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, &rules); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		rules, err = s.gCtx.Inst().Mongo.FetchAlertRules(ctx)
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			rules = defaultRules
+		}
+	}
+
+	if err := Validate(rules); err != nil {
+		return err
+	}
+
+	mp := make(map[string]datastructures.AlertRules, len(rules))
+	for _, r := range rules {
+		mp[r.Event] = r
+	}
+
+	s.mtx.Lock()
+	s.rules = mp
+	s.mtx.Unlock()
+
+	return nil
+}
+
+func (s *Store) Evaluate(event string, amount float64, tier string) Result {
+	s.mtx.RLock()
+	r, ok := s.rules[event]
+	s.mtx.RUnlock()
+	if !ok {
+		return Result{Skip: true}
+	}
+
+	return Evaluate(r, amount, tier)
+}
+
+func knownTag(tag string) bool {
+	for _, v := range markup.Tags {
+		if v == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+)
+
+func rulesFor(t *testing.T, event string) datastructures.AlertRules {
+	t.Helper()
+	for _, r := range defaultRules {
+		if r.Event == event {
+			return r
+		}
+	}
+	t.Fatalf("no default rules for %s", event)
+	return datastructures.AlertRules{}
+}
+
+type evaluateTest struct {
+	name   string
+	amount float64
+	tier   string
+	skip   bool
+	alert  string
+}
+
+func runEvaluate(t *testing.T, event string, tests []evaluateTest) {
+	r := rulesFor(t, event)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Evaluate(r, tt.amount, tt.tier)
+			if res.Skip != tt.skip {
+				t.Fatalf("skip is %v, want %v", res.Skip, tt.skip)
+			}
+			if !tt.skip && res.Alert.Name != tt.alert {
+				t.Fatalf("alert is %s, want %s", res.Alert.Name, tt.alert)
+			}
+		})
+	}
+}
+
+func TestEvaluateCheer(t *testing.T) {
+	runEvaluate(t, datastructures.AlertTypeCheer, []evaluateTest{
+		{"below minimum", 299, "", true, ""},
+		{"minimum", 300, "", false, "CheerDefault"},
+		{"below 500", 499, "", false, "CheerDefault"},
+		{"500", 500, "", false, "Cheer500"},
+		{"1000", 1000, "", false, "Cheer1000"},
+		{"10000", 25000, "", false, "Cheer10000"},
+		{"100000", 100000, "", false, "Cheer100000"},
+	})
+}
+
+func TestEvaluateDonation(t *testing.T) {
+	runEvaluate(t, datastructures.AlertTypeDonation, []evaluateTest{
+		{"below minimum", 2.99, "", true, ""},
+		{"minimum", 3, "", false, "DonationDefault"},
+		{"exactly 4.20", 4.2, "", false, "Donation420"},
+		{"above 4.20", 4.21, "", false, "DonationDefault"},
+		{"below 4.20", 4.19, "", false, "DonationDefault"},
+		{"10", 10, "", false, "Donation10"},
+		{"50", 69, "", false, "Donation50"},
+	})
+}
+
+func TestEvaluateSubscriber(t *testing.T) {
+	runEvaluate(t, datastructures.AlertTypeSubscriber, []evaluateTest{
+		{"first month", 1, "1000", false, "Subscriber1"},
+		{"2 months", 2, "1000", false, "Subscriber2"},
+		{"between tiers", 7, "1000", false, "Subscriber6"},
+		{"18 months", 18, "1000", false, "Subscriber18"},
+		{"60 months", 72, "1000", false, "Subscriber60"},
+		{"tier 2", 1, "2000", false, "SubscriberSuper"},
+		{"tier 2 overrides months", 60, "2000", false, "SubscriberSuper"},
+		{"tier 3", 12, "3000", false, "SubscriberMega"},
+	})
+}
+
+func TestEvaluateSubscriberVoices(t *testing.T) {
+	r := rulesFor(t, datastructures.AlertTypeSubscriber)
+
+	has := func(voices []string, voice string) bool {
+		for _, v := range voices {
+			if v == voice {
+				return true
+			}
+		}
+		return false
+	}
+
+	if res := Evaluate(r, 17, "1000"); has(res.Voices, "steve") {
+		t.Fatal("steve is unlocked before 18 months")
+	}
+	if res := Evaluate(r, 18, "1000"); !has(res.Voices, "steve") || !has(res.Voices, "krab") {
+		t.Fatalf("18 months unlock %v", res.Voices)
+	}
+}
+
+func TestEvaluateGift(t *testing.T) {
+	runEvaluate(t, datastructures.AlertTypeGift, []evaluateTest{
+		{"single", 1, "1000", false, "SubscriberGift"},
+		{"4", 4, "1000", false, "SubscriberGift"},
+		{"5", 5, "1000", false, "SubscriberGift5"},
+		{"25", 30, "2000", false, "SubscriberGift25"},
+		{"95", 100, "3000", false, "SubscriberGift95"},
+	})
+
+	if res := Evaluate(rulesFor(t, datastructures.AlertTypeGift), 1, "1000"); res.Alert.Type != datastructures.AlertTypeSubscriber {
+		t.Fatalf("gift alerts are of type %s", res.Alert.Type)
+	}
+}
+
+func TestValidateDuplicate(t *testing.T) {
+	rules := []datastructures.AlertRules{rulesFor(t, datastructures.AlertTypeCheer), rulesFor(t, datastructures.AlertTypeCheer)}
+	if err := Validate(rules); err == nil {
+		t.Fatal("duplicate rules are valid")
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+type authUser struct {
+	ID    string `json:"id"`
+	Login string `json:"login"`
+}
+
+// Auth only lets through users logged in with twitch, their id is stored in the "user_id" local.
+func Auth(ctx global.Context) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		user := authUser{}
+		if err := jwt.Verify(ctx.Config().JwtSecret, c.Cookies("tts_auth"), &user); err != nil || user.ID == "" {
+			return c.SendStatus(401)
+		}
+
+		c.Locals("user_id", user.ID)
+
+		return c.Next()
+	}
+}
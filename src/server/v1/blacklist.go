@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/server/middleware"
+	matcher "github.com/admiralbulldogtv/yappercontroller/src/textparser/blacklist"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Blacklist manages the blacklist, global entries can only be managed by globally whitelisted users.
+func Blacklist(ctx global.Context, app fiber.Router) {
+	app.Use(middleware.Auth(ctx))
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		channelID := primitive.NilObjectID
+		if v := c.Query("channel_id"); v != "" {
+			id, err := primitive.ObjectIDFromHex(v)
+			if err != nil {
+				return c.SendStatus(400)
+			}
+			channelID = id
+		}
+		if !canManage(ctx, c.Locals("user_id").(string), channelID) {
+			return c.SendStatus(403)
+		}
+
+		entries, err := ctx.Inst().Blacklist.Entries(c.Context(), channelID)
+		if err != nil {
+			logrus.WithError(err).Error("failed to fetch blacklist")
+			return c.SendStatus(500)
+		}
+
+		return c.Status(200).JSON(entries)
+	})
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		entry := datastructures.BlacklistEntry{}
+		if err := json.Unmarshal(c.Body(), &entry); err != nil {
+			return c.SendStatus(400)
+		}
+		if !canManage(ctx, c.Locals("user_id").(string), entry.ChannelID) {
+			return c.SendStatus(403)
+		}
+
+		entry, err := ctx.Inst().Blacklist.Add(c.Context(), entry)
+		if err != nil {
+			if errors.Is(err, matcher.ErrInvalidEntry) {
+				return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+			}
+			logrus.WithError(err).Error("failed to add blacklist entry")
+			return c.SendStatus(500)
+		}
+
+		return c.Status(201).JSON(entry)
+	})
+
+	app.Delete("/:id", func(c *fiber.Ctx) error {
+		id, err := primitive.ObjectIDFromHex(c.Params("id"))
+		if err != nil {
+			return c.SendStatus(404)
+		}
+
+		entry, err := ctx.Inst().Mongo.FetchBlacklistEntry(c.Context(), id)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.SendStatus(404)
+			}
+			logrus.WithError(err).Error("failed to fetch blacklist entry")
+			return c.SendStatus(500)
+		}
+		if !canManage(ctx, c.Locals("user_id").(string), entry.ChannelID) {
+			return c.SendStatus(404)
+		}
+
+		if err = ctx.Inst().Blacklist.Remove(c.Context(), id); err != nil {
+			logrus.WithError(err).Error("failed to remove blacklist entry")
+			return c.SendStatus(500)
+		}
+
+		return c.SendStatus(204)
+	})
+}
@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/server/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cache exposes the stats of the segment cache, it is shared by every channel so only globally whitelisted users can use it.
+func Cache(ctx global.Context, app fiber.Router) {
+	app.Use(middleware.Auth(ctx))
+
+	app.Use(func(c *fiber.Ctx) error {
+		if !canManage(ctx, c.Locals("user_id").(string), primitive.NilObjectID) {
+			return c.SendStatus(403)
+		}
+		return c.Next()
+	})
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		stats, err := ctx.Inst().Cache.Stats(c.Context())
+		if err != nil {
+			logrus.WithError(err).Error("failed to fetch cache stats")
+			return c.SendStatus(500)
+		}
+
+		return c.Status(200).JSON(stats)
+	})
+
+	app.Delete("/", func(c *fiber.Ctx) error {
+		if err := ctx.Inst().Cache.Clear(c.Context()); err != nil {
+			logrus.WithError(err).Error("failed to clear cache")
+			return c.SendStatus(500)
+		}
+
+		return c.SendStatus(204)
+	})
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/deadletters"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/server/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// canManage checks if a user is whitelisted globally or for the channel.
+func canManage(ctx global.Context, userID string, channelID primitive.ObjectID) bool {
+	for _, v := range ctx.Config().Twitch.WhitelistedAccounts {
+		if v == userID {
+			return true
+		}
+	}
+
+	channel, ok := ctx.Inst().Channels.Get(channelID)
+	if !ok {
+		return false
+	}
+
+	for _, v := range channel.WhitelistedAccounts {
+		if v == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func DeadLetters(ctx global.Context, app fiber.Router) {
+	app.Use(middleware.Auth(ctx))
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		channelID := primitive.NilObjectID
+		if v := c.Query("channel_id"); v != "" {
+			id, err := primitive.ObjectIDFromHex(v)
+			if err != nil {
+				return c.SendStatus(400)
+			}
+			channelID = id
+		}
+
+		letters, err := ctx.Inst().DeadLetters.List(c.Context(), channelID)
+		if err != nil {
+			logrus.WithError(err).Error("failed to fetch dead letters")
+			return c.SendStatus(500)
+		}
+
+		out := []datastructures.DeadLetter{}
+		for _, v := range letters {
+			if canManage(ctx, userID, v.ChannelID) {
+				out = append(out, v)
+			}
+		}
+
+		return c.Status(200).JSON(out)
+	})
+
+	lookup := func(c *fiber.Ctx) (datastructures.DeadLetter, error) {
+		id, err := primitive.ObjectIDFromHex(c.Params("id"))
+		if err != nil {
+			return datastructures.DeadLetter{}, mongo.ErrNoDocuments
+		}
+
+		letter, err := ctx.Inst().Mongo.FetchDeadLetter(c.Context(), id)
+		if err == nil && !canManage(ctx, c.Locals("user_id").(string), letter.ChannelID) {
+			err = mongo.ErrNoDocuments
+		}
+		return letter, err
+	}
+
+	app.Post("/:id/retry", func(c *fiber.Ctx) error {
+		letter, err := lookup(c)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.SendStatus(404)
+			}
+			logrus.WithError(err).Error("failed to fetch dead letter")
+			return c.SendStatus(500)
+		}
+
+		if err = ctx.Inst().DeadLetters.Retry(c.Context(), letter.ID); err != nil {
+			if err == deadletters.ErrRetrying {
+				return c.SendStatus(409)
+			}
+			logrus.WithError(err).Error("failed to retry dead letter")
+			return c.SendStatus(500)
+		}
+
+		return c.SendStatus(204)
+	})
+
+	app.Delete("/:id", func(c *fiber.Ctx) error {
+		letter, err := lookup(c)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.SendStatus(404)
+			}
+			logrus.WithError(err).Error("failed to fetch dead letter")
+			return c.SendStatus(500)
+		}
+
+		if err = ctx.Inst().DeadLetters.Discard(c.Context(), letter.ID); err != nil {
+			logrus.WithError(err).Error("failed to discard dead letter")
+			return c.SendStatus(500)
+		}
+
+		return c.SendStatus(204)
+	})
+}
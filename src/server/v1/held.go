@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/queue"
+	"github.com/admiralbulldogtv/yappercontroller/src/server/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type heldEdit struct {
+	Text string `json:"text"`
+}
+
+// Held lets moderators act on the messages a channel holds for approval.
+func Held(ctx global.Context, app fiber.Router) {
+	app.Use(middleware.Auth(ctx))
+
+	channel := func(c *fiber.Ctx) (primitive.ObjectID, bool) {
+		id, err := primitive.ObjectIDFromHex(c.Params("channel"))
+		if err != nil || !canManage(ctx, c.Locals("user_id").(string), id) {
+			return primitive.NilObjectID, false
+		}
+		return id, true
+	}
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		channelID, ok := channel(c)
+		if !ok {
+			return c.SendStatus(404)
+		}
+
+		held, err := ctx.Inst().Queue.Held(c.Context(), channelID)
+		if err != nil {
+			logrus.WithError(err).Error("failed to fetch held messages")
+			return c.SendStatus(500)
+		}
+
+		return c.Status(200).JSON(held)
+	})
+
+	action := func(fn func(c *fiber.Ctx, channelID, id primitive.ObjectID) error) func(c *fiber.Ctx) error {
+		return func(c *fiber.Ctx) error {
+			channelID, ok := channel(c)
+			if !ok {
+				return c.SendStatus(404)
+			}
+			id, err := primitive.ObjectIDFromHex(c.Params("id"))
+			if err != nil {
+				return c.SendStatus(404)
+			}
+
+			if err = fn(c, channelID, id); err != nil {
+				switch err {
+				case fiber.ErrBadRequest:
+					return c.SendStatus(400)
+				case queue.ErrNotFound:
+					return c.SendStatus(404)
+				case queue.ErrNotHeld:
+					return c.SendStatus(409)
+				}
+				logrus.WithError(err).Error("failed to update held message")
+				return c.SendStatus(500)
+			}
+
+			return c.SendStatus(204)
+		}
+	}
+
+	app.Post("/:id/approve", action(func(c *fiber.Ctx, channelID, id primitive.ObjectID) error {
+		return ctx.Inst().Queue.Approve(c.Context(), channelID, id)
+	}))
+
+	app.Post("/:id/reject", action(func(c *fiber.Ctx, channelID, id primitive.ObjectID) error {
+		return ctx.Inst().Queue.Reject(c.Context(), channelID, id)
+	}))
+
+	app.Post("/:id/edit", action(func(c *fiber.Ctx, channelID, id primitive.ObjectID) error {
+		body := heldEdit{}
+		if err := json.Unmarshal(c.Body(), &body); err != nil {
+			return fiber.ErrBadRequest
+		}
+		return ctx.Inst().Queue.Edit(c.Context(), channelID, id, body.Text)
+	}))
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/server/middleware"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/dictionary"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Pronunciations manages the pronunciation dictionary, global entries can only be managed by globally whitelisted users.
+func Pronunciations(ctx global.Context, app fiber.Router) {
+	app.Use(middleware.Auth(ctx))
+
+	add := func(c *fiber.Ctx, entries []datastructures.Pronunciation) error {
+		for _, e := range entries {
+			if !canManage(ctx, c.Locals("user_id").(string), e.ChannelID) {
+				return c.SendStatus(403)
+			}
+		}
+
+		entries, err := ctx.Inst().Pronunciations.Add(c.Context(), entries...)
+		if err != nil {
+			if errors.Is(err, dictionary.ErrInvalidEntry) {
+				return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+			}
+			logrus.WithError(err).Error("failed to add pronunciations")
+			return c.SendStatus(500)
+		}
+
+		return c.Status(201).JSON(entries)
+	}
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		channelID := primitive.NilObjectID
+		if v := c.Query("channel_id"); v != "" {
+			id, err := primitive.ObjectIDFromHex(v)
+			if err != nil {
+				return c.SendStatus(400)
+			}
+			channelID = id
+		}
+		if !canManage(ctx, c.Locals("user_id").(string), channelID) {
+			return c.SendStatus(403)
+		}
+
+		entries, err := ctx.Inst().Pronunciations.Entries(c.Context(), channelID)
+		if err != nil {
+			logrus.WithError(err).Error("failed to fetch pronunciations")
+			return c.SendStatus(500)
+		}
+
+		return c.Status(200).JSON(entries)
+	})
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		entry := datastructures.Pronunciation{}
+		if err := json.Unmarshal(c.Body(), &entry); err != nil {
+			return c.SendStatus(400)
+		}
+
+		return add(c, []datastructures.Pronunciation{entry})
+	})
+
+	// import takes a list of entries, such as the emotes of a channel, which are stored together or not at all.
+	app.Post("/import", func(c *fiber.Ctx) error {
+		entries := []datastructures.Pronunciation{}
+		if err := json.Unmarshal(c.Body(), &entries); err != nil || len(entries) == 0 {
+			return c.SendStatus(400)
+		}
+
+		return add(c, entries)
+	})
+
+	app.Delete("/:id", func(c *fiber.Ctx) error {
+		id, err := primitive.ObjectIDFromHex(c.Params("id"))
+		if err != nil {
+			return c.SendStatus(404)
+		}
+
+		entry, err := ctx.Inst().Mongo.FetchPronunciation(c.Context(), id)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.SendStatus(404)
+			}
+			logrus.WithError(err).Error("failed to fetch pronunciation")
+			return c.SendStatus(500)
+		}
+		if !canManage(ctx, c.Locals("user_id").(string), entry.ChannelID) {
+			return c.SendStatus(404)
+		}
+
+		if err = ctx.Inst().Pronunciations.Remove(c.Context(), id); err != nil {
+			logrus.WithError(err).Error("failed to remove pronunciation")
+			return c.SendStatus(500)
+		}
+
+		return c.SendStatus(204)
+	})
+}
@@ -15,5 +15,17 @@ func Api(ctx global.Context, app fiber.Router) {
 
 	Twitch(ctx, app.Group("/twitch"))
 
+	DeadLetters(ctx, app.Group("/dead-letters"))
+
+	Held(ctx, app.Group("/channels/:channel/held"))
+
+	Blacklist(ctx, app.Group("/blacklist"))
+
+	Pronunciations(ctx, app.Group("/pronunciations"))
+
+	Cache(ctx, app.Group("/cache"))
+
+	Workers(ctx, app.Group("/workers"))
+
 	Alerts(app.Group("/alerts"))
 }
@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/server/middleware"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Workers lists the live tts workers of this controller, only globally whitelisted users can see them.
+func Workers(ctx global.Context, app fiber.Router) {
+	app.Use(middleware.Auth(ctx))
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		if !canManage(ctx, c.Locals("user_id").(string), primitive.NilObjectID) {
+			return c.SendStatus(403)
+		}
+
+		return c.Status(200).JSON(ctx.Inst().Workers.All())
+	})
+}
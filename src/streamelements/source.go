@@ -0,0 +1,232 @@
+package streamelements
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/events"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+// Source is the StreamElements connection of a channel.
+type Source interface {
+	events.Source
+	Close() error
+}
+
+type source struct {
+	gCtx    global.Context
+	channel datastructures.Channel
+	cl      Client
+	auth    chan error
+	events  chan events.Event
+	close   sync.Once
+	done    chan struct{}
+}
+
+// NewSource connects to StreamElements for a channel and emits every cheer, donation and subscription as an event.
+func NewSource(ctx global.Context, channel datastructures.Channel) (Source, error) {
+	s := &source{
+		gCtx:    ctx,
+		channel: channel,
+		cl:      NewClient(),
+		auth:    make(chan error, 1),
+		events:  make(chan events.Event, 100),
+		done:    make(chan struct{}),
+	}
+
+	go s.process()
+
+	if err := s.connect(); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close disconnects from StreamElements, the events channel is closed once the connection is gone.
+func (s *source) Close() error {
+	s.close.Do(func() {
+		close(s.done)
+	})
+
+	return s.cl.Close()
+}
+
+func (s *source) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *source) Name() string {
+	return fmt.Sprintf("streamelements:%s", s.channel.Name)
+}
+
+func (s *source) Events() <-chan events.Event {
+	return s.events
+}
+
+func (s *source) connect() error {
+	ctx, cancel := global.WithTimeout(s.gCtx, time.Second*10)
+	defer cancel()
+
+	if err := s.cl.Connect(ctx.Config().StreamElements.WssURL); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-s.auth:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *source) process() {
+	defer close(s.events)
+	for {
+		var event Event
+		select {
+		case <-s.done:
+			return
+		case event = <-s.cl.Events():
+		}
+
+		switch event.Name {
+		case "connect":
+			logrus.Info("streamelements connected")
+			if err := s.cl.Auth(s.channel.StreamElements.AuthMethod, s.channel.StreamElements.AuthToken); err != nil {
+				panic(err)
+			}
+		case "disconnect":
+			logrus.Warn("streamelements disconnected")
+			go func() {
+				time.Sleep(time.Second)
+				if s.isClosed() {
+					return
+				}
+				if err := s.connect(); err != nil {
+					panic(err)
+				}
+			}()
+		case "authenticated":
+			s.auth <- nil
+		case "unauthorized":
+			s.auth <- fmt.Errorf("%s", event.Payload)
+		case "event:update", "event:test":
+			var (
+				evnt    string
+				payload jsoniter.RawMessage
+			)
+
+			if event.Name == "event:update" {
+				// donation/sub/cheer
+				se := EventUpdatePayload{}
+				if err := json.Unmarshal(event.Payload, &se); err != nil {
+					logrus.WithError(err).Error("failed to parse event")
+					continue
+				}
+				evnt = se.Name
+				payload = se.Data
+			} else {
+				// donation/sub/cheer
+				se := EventTestPayload{}
+				if err := json.Unmarshal(event.Payload, &se); err != nil {
+					logrus.WithError(err).Error("failed to parse event")
+					continue
+				}
+				evnt = se.Listener
+				payload = se.Event
+			}
+
+			e, ok, err := parseEvent(evnt, payload)
+			if err != nil {
+				logrus.WithError(err).Error("failed to parse event")
+				continue
+			}
+			if ok {
+				e.ChannelID = s.channel.ID
+				s.events <- e
+			}
+		}
+	}
+}
+
+func parseEvent(evnt string, payload jsoniter.RawMessage) (events.Event, bool, error) {
+	e := events.Event{}
+	switch evnt {
+	case EventListenerCheer:
+		data := Cheer{}
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return e, false, err
+		}
+		e.Source = datastructures.AudioTriggerSourceBits
+		e.Username = data.DisplayName
+		e.Bits = data.Amount
+		e.Message = data.Message
+	case EventListenerDonation:
+		data := Donation{}
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return e, false, err
+		}
+		e.Source = datastructures.AudioTriggerSourceDonation
+		e.Username = strings.ReplaceAll(data.Name, " ", "")
+		e.Amount = data.Amount
+		e.Currency = strings.ToUpper(data.Currency)
+		e.Message = data.Message
+	case EventListenerSubscription:
+		data := Subscription{}
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return e, false, err
+		}
+		switch {
+		case data.BulkGifted:
+			// a community gift, the amount is how many subs were gifted.
+			e.Source = datastructures.AudioTriggerSourceGift
+			e.Username = data.Sender
+			if e.Username == "" {
+				e.Username = data.Name
+			}
+			e.Login = login(e.Username)
+			e.Gifts = data.Amount
+			e.Tier = events.Tier(data.Tier)
+			return e, true, nil
+		case data.Gifted && data.IsCommunityGift:
+			// the subs of a community gift are covered by the bulk event.
+			return e, false, nil
+		case data.Gifted:
+			e.Source = datastructures.AudioTriggerSourceGift
+			e.Username = data.Sender
+			e.Recipient = data.Name
+			e.Login = login(data.Sender)
+			e.Gifts = 1
+			e.Tier = events.Tier(data.Tier)
+			return e, true, nil
+		}
+		e.Source = datastructures.AudioTriggerSourceSub
+		e.Username = data.Name
+		e.Months = data.Amount
+		e.Tier = events.Tier(data.Tier)
+		e.Message = data.Message
+	default:
+		return e, false, nil
+	}
+
+	return e, true, nil
+}
+
+// login turns the name of a gifter into a twitch login, StreamElements only reports the display name of
+// the gifter which is the login in another case.
+func login(name string) string {
+	return strings.ToLower(name)
+}
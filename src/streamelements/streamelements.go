@@ -26,6 +26,7 @@ type Client interface {
 	Auth(method, token string) error
 	RawMessage(event string, payload interface{}) error
 	Events() <-chan Event
+	Close() error
 }
 
 type EventUpdatePayload struct {
@@ -39,14 +40,15 @@ type EventTestPayload struct {
 }
 
 type Subscription struct {
-	Name       string      `json:"name"`
-	Amount     int         `json:"amount"`
-	Tier       interface{} `json:"tier"`
-	Count      int         `json:"count"`
-	Gifted     bool        `json:"gifted"`
-	BulkGifted bool        `json:"bulkGifted"`
-	Sender     string      `json:"sender"`
-	Message    string      `json:"message"`
+	Name            string      `json:"name"`
+	Amount          int         `json:"amount"`
+	Tier            interface{} `json:"tier"`
+	Count           int         `json:"count"`
+	Gifted          bool        `json:"gifted"`
+	BulkGifted      bool        `json:"bulkGifted"`
+	IsCommunityGift bool        `json:"isCommunityGift"`
+	Sender          string      `json:"sender"`
+	Message         string      `json:"message"`
 }
 
 type Cheer struct {
@@ -57,9 +59,10 @@ type Cheer struct {
 }
 
 type Donation struct {
-	Name    string  `json:"name"`
-	Amount  float64 `json:"amount"`
-	Message string  `json:"message"`
+	Name     string  `json:"name"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	Message  string  `json:"message"`
 }
 
 type Event struct {
@@ -112,6 +115,13 @@ func (c *cl) Connect(uri string) error {
 	return nil
 }
 
+func (c *cl) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
 func (c *cl) Auth(method, token string) error {
 	return c.RawMessage("authenticate", map[string]string{
 		"method": method,
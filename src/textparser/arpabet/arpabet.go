@@ -0,0 +1,97 @@
+package arpabet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
+)
+
+var re = regexp.MustCompile(`\{([^{}]*)\}`)
+
+var vowels = map[string]bool{
+	"AA": true, "AE": true, "AH": true, "AO": true, "AW": true, "AY": true, "EH": true, "ER": true,
+	"EY": true, "IH": true, "IY": true, "OW": true, "OY": true, "UH": true, "UW": true,
+}
+
+var consonants = map[string]bool{
+	"B": true, "CH": true, "D": true, "DH": true, "F": true, "G": true, "HH": true, "JH": true, "K": true,
+	"L": true, "M": true, "N": true, "NG": true, "P": true, "R": true, "S": true, "SH": true, "T": true,
+	"TH": true, "V": true, "W": true, "Y": true, "Z": true, "ZH": true,
+}
+
+// InvalidError is returned for a pronunciation which uses symbols that are not ARPAbet.
+type InvalidError struct {
+	Pronunciation string
+	Symbols       []string
+}
+
+func (e *InvalidError) Error() string {
+	if len(e.Symbols) == 0 {
+		return "empty pronunciation {}"
+	}
+	return fmt.Sprintf("invalid phonemes %s in {%s}, use ARPAbet like {HH AH0 L OW1}", strings.Join(e.Symbols, ", "), e.Pronunciation)
+}
+
+// Valid reports if a symbol is an ARPAbet phoneme, vowels can be followed by a stress of 0, 1 or 2.
+func Valid(symbol string) bool {
+	if consonants[symbol] {
+		return true
+	}
+	if l := len(symbol); l == 3 && symbol[2] >= '0' && symbol[2] <= '2' {
+		symbol = symbol[:2]
+	}
+	return vowels[symbol]
+}
+
+// Parse validates a pronunciation like "HH AH0 L OW1" and returns it in the form the workers expect.
+func Parse(pronunciation string) (string, error) {
+	symbols := strings.Fields(strings.ToUpper(pronunciation))
+	invalid := []string{}
+	for _, s := range symbols {
+		if !Valid(s) {
+			invalid = append(invalid, s)
+		}
+	}
+	if len(symbols) == 0 || len(invalid) != 0 {
+		return "", &InvalidError{Pronunciation: strings.Join(symbols, " "), Symbols: invalid}
+	}
+
+	return "{" + strings.Join(symbols, " ") + "}", nil
+}
+
+// NormalizeArpabet splits pronunciations like {HH AH0 L OW1} out of the text,
+// the following stages leave these parts alone so they reach the workers as they are.
+func NormalizeArpabet(pts []parts.VoicePart) ([]parts.VoicePart, error) {
+	rPts := []parts.VoicePart{}
+	for _, v := range pts {
+		if v.Type != parts.PartTypeRaw {
+			rPts = append(rPts, v)
+			continue
+		}
+
+		text := v.Value
+		for {
+			idx := re.FindStringSubmatchIndex(text)
+			if idx == nil {
+				break
+			}
+			value, err := Parse(text[idx[2]:idx[3]])
+			if err != nil {
+				return nil, err
+			}
+
+			if preText := strings.TrimSpace(text[:idx[0]]); preText != "" {
+				rPts = append(rPts, parts.VoicePart{Value: preText, Type: parts.PartTypeRaw, Style: v.Style})
+			}
+			rPts = append(rPts, parts.VoicePart{Value: value, Type: parts.PartTypeArpabet, Style: v.Style})
+			text = text[idx[1]:]
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			rPts = append(rPts, parts.VoicePart{Value: text, Type: parts.PartTypeRaw, Style: v.Style})
+		}
+	}
+
+	return rPts, nil
+}
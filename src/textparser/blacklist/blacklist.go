@@ -0,0 +1,236 @@
+package blacklist
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+)
+
+var (
+	ErrBlacklisted  = fmt.Errorf("blacklisted phrases")
+	ErrInvalidEntry = fmt.Errorf("invalid blacklist entry")
+)
+
+// Bleep is what a bleeped match is replaced with, it is played as the bleep sound byte
+// and read as "bleep" when the sound cannot be used.
+const Bleep = "(bleep)"
+
+// Defaults are stored when there is no blacklist yet.
+var Defaults = []datastructures.BlacklistEntry{
+	{Type: datastructures.BlacklistTypeRegex, Pattern: `\bgor(?:gc|p)_?s?\b`, Severity: datastructures.BlacklistSeverityReject},
+	{Type: datastructures.BlacklistTypeWord, Pattern: "knee", Severity: datastructures.BlacklistSeverityReject},
+}
+
+// confusables maps leetspeak and characters which look like latin letters to the letter.
+var confusables = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '€': 'e',
+	// cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o',
+	'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's',
+	// greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+	// accents
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ç': 'c', 'è': 'e',
+	'é': 'e', 'ê': 'e', 'ë': 'e', 'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o', 'ù': 'u', 'ú': 'u',
+	'û': 'u', 'ü': 'u', 'ý': 'y', 'ÿ': 'y',
+}
+
+// symbols are only read as letters inside of a word, so punctuation stays punctuation.
+var symbols = map[rune]rune{
+	'@': 'a', '$': 's', '!': 'i', '|': 'l',
+}
+
+type entry struct {
+	re          *regexp.Regexp
+	severity    string
+	replacement string
+}
+
+// List is a compiled set of blacklist entries.
+type List []entry
+
+// Compile compiles entries, words are matched as whole words after folding, regexes are used as is.
+func Compile(entries []datastructures.BlacklistEntry) (List, error) {
+	l := make(List, 0, len(entries))
+	for _, e := range entries {
+		var (
+			re  *regexp.Regexp
+			err error
+		)
+
+		switch e.Type {
+		case datastructures.BlacklistTypeWord:
+			words := strings.Fields(Fold(e.Pattern))
+			if len(words) == 0 {
+				return nil, fmt.Errorf("%w: empty word", ErrInvalidEntry)
+			}
+			for i, w := range words {
+				words[i] = regexp.QuoteMeta(w)
+			}
+			re, err = regexp.Compile(fmt.Sprintf(`\b%s\b`, strings.Join(words, `\s+`)))
+		case datastructures.BlacklistTypeRegex:
+			re, err = regexp.Compile("(?i)" + e.Pattern)
+		default:
+			return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidEntry, e.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad pattern %s: %s", ErrInvalidEntry, e.Pattern, err)
+		}
+
+		switch e.Severity {
+		case datastructures.BlacklistSeverityReject, datastructures.BlacklistSeverityBleep, datastructures.BlacklistSeverityReplace:
+		default:
+			return nil, fmt.Errorf("%w: unknown severity %s", ErrInvalidEntry, e.Severity)
+		}
+
+		l = append(l, entry{re: re, severity: e.Severity, replacement: e.Replacement})
+	}
+
+	return l, nil
+}
+
+// view is a folded version of a text which remembers where every rune came from.
+type view struct {
+	text  string
+	start []int
+	end   []int
+}
+
+type token struct {
+	runes []rune
+	start []int
+	end   []int
+}
+
+// Fold lowercases text, maps lookalikes to latin letters, turns everything else into spaces
+// and joins spaced out letters like "k n e e" back into a word.
+func Fold(text string) string {
+	return fold(text).text
+}
+
+func fold(text string) view {
+	runes := []rune{}
+	offsets := []int{}
+	for i, r := range text {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	isWord := func(i int) bool {
+		return i >= 0 && i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+	}
+
+	tokens := []*token{}
+	var cur *token
+	for idx, r := range runes {
+		r = unicode.ToLower(r)
+		if m, ok := confusables[r]; ok {
+			r = m
+		} else if m, ok := symbols[r]; ok && isWord(idx-1) && isWord(idx+1) {
+			r = m
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			cur = &token{}
+			tokens = append(tokens, cur)
+		}
+		cur.runes = append(cur.runes, r)
+		cur.start = append(cur.start, offsets[idx])
+		cur.end = append(cur.end, offsets[idx+1])
+	}
+
+	v := view{}
+	sb := strings.Builder{}
+	for i, t := range tokens {
+		if i != 0 && !(len(t.runes) == 1 && len(tokens[i-1].runes) == 1) {
+			sb.WriteByte(' ')
+			v.start = append(v.start, tokens[i-1].end[len(tokens[i-1].end)-1])
+			v.end = append(v.end, t.start[0])
+		}
+		for j, r := range t.runes {
+			// keep one position per byte so regex offsets can be used directly.
+			for k := 0; k < utf8.RuneLen(r); k++ {
+				v.start = append(v.start, t.start[j])
+				v.end = append(v.end, t.end[j])
+			}
+			sb.WriteRune(r)
+		}
+	}
+	v.text = sb.String()
+
+	return v
+}
+
+type match struct {
+	start, end int
+	entry      entry
+}
+
+func (l List) matches(text string) []match {
+	v := fold(text)
+
+	matches := []match{}
+	for _, e := range l {
+		for _, idx := range e.re.FindAllStringIndex(text, -1) {
+			if idx[0] != idx[1] {
+				matches = append(matches, match{start: idx[0], end: idx[1], entry: e})
+			}
+		}
+		for _, idx := range e.re.FindAllStringIndex(v.text, -1) {
+			if idx[0] != idx[1] {
+				matches = append(matches, match{start: v.start[idx[0]], end: v.end[idx[1]-1], entry: e})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	return matches
+}
+
+// Apply checks text against the list, returning ErrBlacklisted when a rejecting entry matches,
+// otherwise bleeped and replaced matches are substituted.
+func (l List) Apply(text string) (string, error) {
+	if len(l) == 0 {
+		return text, nil
+	}
+
+	matches := l.matches(text)
+	for _, m := range matches {
+		if m.entry.severity == datastructures.BlacklistSeverityReject {
+			return "", ErrBlacklisted
+		}
+	}
+
+	sb := strings.Builder{}
+	last := 0
+	for _, m := range matches {
+		if m.start < last {
+			// overlaps a match which has already been replaced.
+			continue
+		}
+		sb.WriteString(text[last:m.start])
+		if m.entry.severity == datastructures.BlacklistSeverityBleep {
+			sb.WriteString(" " + Bleep + " ")
+		} else {
+			sb.WriteString(" " + m.entry.replacement + " ")
+		}
+		last = m.end
+	}
+	sb.WriteString(text[last:])
+
+	return sb.String(), nil
+}
@@ -3,30 +3,106 @@ package currency
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
+	"github.com/gobuffalo/packr/v2"
+	jsoniter "github.com/json-iterator/go"
 )
 
-var re = regexp.MustCompile(`([-+])?\s*(?:([€£\$])\s*([-+])?\s*((?:[1-9]+0*)+(?:\s*,?\s*\d{3})*)?(?:\.(\d+))?|((?:[1-9]+0*)+(?:\s*,?\s*\d{3})*)?(?:\.(\d+))?\s*([€£\$]))`)
-var numberRe = regexp.MustCompile(`[^\d]`)
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
-var symbolMap = map[string]string{
-	"€": "euro",
-	"£": "pound",
-	"$": "dollar",
+// Currency is an entry of the currency table.
+type Currency struct {
+	Code string `json:"code"`
+	// Display is the symbol shown in alerts.
+	Display string `json:"display"`
+	// Match are the lowercase symbols and codes which are read as this currency.
+	Match       []string `json:"match"`
+	Singular    string   `json:"singular"`
+	Plural      string   `json:"plural"`
+	Minor       string   `json:"minor"`
+	MinorPlural string   `json:"minor_plural"`
+	ZeroDecimal bool     `json:"zero_decimal"`
 }
 
-var symbolMapCents = map[string]string{
-	"€": "cents",
-	"£": "pence",
-	"$": "cents",
+var (
+	re       *regexp.Regexp
+	numberRe = regexp.MustCompile(`[^\d]`)
+)
+
+// Currencies maps ISO currency codes to their entry.
+var Currencies = map[string]Currency{}
+
+// Symbols maps ISO currency codes to the symbol shown in alerts.
+var Symbols = map[string]string{}
+
+// tokens maps everything in the text which can mark an amount as money to its currency.
+var tokens = map[string]Currency{}
+
+var magnitudes = map[string]string{
+	"k":        "thousand",
+	"thousand": "thousand",
+	"m":        "million",
+	"mil":      "million",
+	"million":  "million",
+	"b":        "billion",
+	"bn":       "billion",
+	"billion":  "billion",
 }
 
-var symbolMapCent = map[string]string{
-	"€": "cent",
-	"£": "penny",
-	"$": "cent",
+func init() {
+	box := packr.New("currency-static", "./static")
+	data, err := box.Find("currencies.json")
+	if err != nil {
+		panic(err)
+	}
+
+	table := []Currency{}
+	if err := json.Unmarshal(data, &table); err != nil {
+		panic(err)
+	}
+
+	matches := []string{}
+	for _, c := range table {
+		Currencies[c.Code] = c
+		Symbols[c.Code] = c.Display
+		for _, m := range c.Match {
+			tokens[m] = c
+			matches = append(matches, m)
+		}
+	}
+
+	// longer tokens first so "us$" wins over "$".
+	sort.Slice(matches, func(i, j int) bool {
+		return len(matches[i]) > len(matches[j])
+	})
+	for i, m := range matches {
+		matches[i] = regexp.QuoteMeta(m)
+	}
+	tok := strings.Join(matches, "|")
+	num := `(\d+(?:,\d{3})*)?(?:\.(\d+))?`
+	mag := `(?:\s?(thousand|million|billion|mil|bn|k|m|b))?`
+
+	re = regexp.MustCompile(fmt.Sprintf(`([-+])?\s*(?:(%[1]s)\s*([-+])?\s*%[2]s%[3]s|%[2]s%[3]s\s*(%[1]s))`, tok, num, mag))
+}
+
+// FormatAmount formats an amount with the symbol of its currency, unknown currencies are suffixed with their code.
+func FormatAmount(amount float64, code string) string {
+	code = strings.ToUpper(code)
+	format := "%.2f"
+	if Currencies[code].ZeroDecimal {
+		format = "%.0f"
+	}
+	number := fmt.Sprintf(format, amount)
+
+	if symbol, ok := Symbols[code]; ok {
+		return symbol + number
+	}
+
+	return fmt.Sprintf("%s %s", number, code)
 }
 
 func NormalizeCurrency(pts []parts.VoicePart) []parts.VoicePart {
@@ -38,8 +114,16 @@ func NormalizeCurrency(pts []parts.VoicePart) []parts.VoicePart {
 		}
 
 		text := strings.TrimSpace(p.Value)
-		matches := re.FindAllStringSubmatch(text, -1)
-		for _, match := range matches {
+		last := 0
+		for _, idx := range re.FindAllStringSubmatchIndex(text, -1) {
+			match := make([]string, len(idx)/2)
+			for i := range match {
+				if idx[i*2] != -1 {
+					match[i] = text[idx[i*2]:idx[i*2+1]]
+				}
+			}
+
+			start, end := idx[0], idx[1]
 			neg := match[1]
 			symbol := match[2]
 			neg2 := match[3]
@@ -52,33 +136,58 @@ func NormalizeCurrency(pts []parts.VoicePart) []parts.VoicePart {
 			if neg == "+" {
 				neg = ""
 			}
-			number := match[4]
-			decimal := match[5]
+			number, decimal, magnitude := match[4], match[5], match[6]
 			if symbol == "" {
-				number = match[6]
-				decimal = match[7]
-				symbol = match[8]
+				number, decimal, magnitude = match[7], match[8], match[9]
+				symbol = match[10]
+			} else if magnitude != "" && end < len(text) && isWordChar(text[end]) {
+				// "$5 but" is not five billion dollars.
+				end = idx[9]
+				if idx[11] != -1 {
+					end = idx[11]
+				}
+				magnitude = ""
 			}
 			if number == "" && decimal == "" {
 				continue
 			}
 
-			currency := symbolMap[symbol]
+			// codes and symbols made of letters cannot be part of a word.
+			for start < end && text[start] == ' ' {
+				start++
+			}
+			if start != 0 && isWordChar(text[start-1]) || end < len(text) && isWordChar(text[end]) {
+				continue
+			}
+			if start < last {
+				continue
+			}
+
+			c := tokens[symbol]
 			number = numberRe.ReplaceAllString(number, "")
-			if number != "1" {
-				currency += "s"
+			name := c.Singular
+			if number != "1" || magnitude != "" {
+				name = c.Plural
 			}
 
 			var format string
 
-			if decimal != "" {
+			if magnitude != "" {
+				if number == "" {
+					number = "0"
+				}
+				if decimal != "" {
+					number = fmt.Sprintf("%s.%s", number, decimal)
+				}
+				format = fmt.Sprintf("%s %s %s", number, magnitudes[magnitude], c.Plural)
+			} else if decimal != "" {
 				if len(decimal) <= 2 {
 					if len(decimal) == 1 {
 						decimal += "0"
 					}
-					cents := symbolMapCents[symbol]
+					cents := c.MinorPlural
 					if decimal == "01" {
-						cents = symbolMapCent[symbol]
+						cents = c.Minor
 					}
 					if decimal[0] == '0' {
 						decimal = decimal[1:]
@@ -88,55 +197,51 @@ func NormalizeCurrency(pts []parts.VoicePart) []parts.VoicePart {
 					if isZero(number) {
 						format = decimal
 					} else {
-						format = fmt.Sprintf("%s %s and %s", number, currency, decimal)
+						format = fmt.Sprintf("%s %s and %s", number, name, decimal)
 					}
 				} else if number == "" {
-					format = fmt.Sprintf("0.%s %s", decimal, currency)
+					format = fmt.Sprintf("0.%s %s", decimal, c.Plural)
 				} else {
-					format = fmt.Sprintf("%s.%s %s", number, decimal, currency)
+					format = fmt.Sprintf("%s.%s %s", number, decimal, c.Plural)
 				}
 			} else {
-				format = fmt.Sprintf("%s %s", number, currency)
+				format = fmt.Sprintf("%s %s", number, name)
 			}
 
 			if neg == "-" {
 				format = "minus " + format
 			}
 
-			mtch := strings.TrimSpace(match[0])
-
-			idx := strings.Index(text, mtch)
-			idx2 := idx + len(mtch)
-			{
-				if idx != 0 && text[idx-1] != ' ' {
-					format = fmt.Sprint(" ", format)
-				}
-				if idx2 < len(text)-1 && text[idx2+1] != ' ' {
-					format = fmt.Sprint(format, " ")
-				}
-			}
-
-			preText := strings.TrimSpace(text[0:idx])
-			text = strings.TrimSpace(text[idx2:])
+			preText := strings.TrimSpace(text[last:start])
+			last = end
 			if preText != "" {
-				for symbol, replace := range symbolMap {
-					preText = replaceAddSpace(preText, symbol, replace)
-				}
-				retParts = append(retParts, parts.VoicePart{Type: parts.PartTypeRaw, Value: preText, Voice: p.Voice, VoicePartMeta: p.VoicePartMeta})
+				retParts = append(retParts, parts.VoicePart{Type: parts.PartTypeRaw, Value: replaceSymbols(preText), Voice: p.Voice, VoicePartMeta: p.VoicePartMeta, Style: p.Style})
 			}
-			retParts = append(retParts, parts.VoicePart{Type: parts.PartTypeCurrency, Value: format, Voice: p.Voice, VoicePartMeta: p.VoicePartMeta})
+			retParts = append(retParts, parts.VoicePart{Type: parts.PartTypeCurrency, Value: format, Voice: p.Voice, VoicePartMeta: p.VoicePartMeta, Style: p.Style})
 		}
-		if text != "" {
-			for symbol, replace := range symbolMap {
-				text = replaceAddSpace(text, symbol, replace)
-			}
-			retParts = append(retParts, parts.VoicePart{Type: parts.PartTypeRaw, Value: text, Voice: p.Voice, VoicePartMeta: p.VoicePartMeta})
+		if text = strings.TrimSpace(text[last:]); text != "" {
+			retParts = append(retParts, parts.VoicePart{Type: parts.PartTypeRaw, Value: replaceSymbols(text), Voice: p.Voice, VoicePartMeta: p.VoicePartMeta, Style: p.Style})
 		}
 	}
 
 	return retParts
 }
 
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// replaceSymbols reads the currency symbols which are not part of an amount.
+func replaceSymbols(text string) string {
+	for symbol, c := range tokens {
+		if strings.IndexFunc(symbol, unicode.IsLetter) != -1 {
+			continue
+		}
+		text = replaceAddSpace(text, symbol, c.Singular)
+	}
+	return text
+}
+
 func replaceAddSpace(text, old, new string) string {
 	edited := text
 	idx := 0
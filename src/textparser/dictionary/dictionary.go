@@ -0,0 +1,127 @@
+package dictionary
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/gobuffalo/packr/v2"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+var ErrInvalidEntry = fmt.Errorf("invalid pronunciation")
+
+// Defaults are the built in pronunciations, stored entries are applied on top of them.
+var Defaults []datastructures.Pronunciation
+
+// Default is the dictionary of only the built in pronunciations.
+var Default *Dictionary
+
+func init() {
+	box := packr.New("dictionary-static", "./static")
+	data, err := box.Find("pronunciations.json")
+	if err != nil {
+		panic(err)
+	}
+
+	if err := json.Unmarshal(data, &Defaults); err != nil {
+		panic(err)
+	}
+
+	if Default, err = Compile(Defaults); err != nil {
+		panic(err)
+	}
+}
+
+type regexEntry struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Dictionary is a compiled set of pronunciations.
+type Dictionary struct {
+	words   map[string]string
+	letters map[string]string
+	regexes []regexEntry
+}
+
+// Compile compiles entries, later entries of the same word replace earlier ones.
+func Compile(entries []datastructures.Pronunciation) (*Dictionary, error) {
+	d := &Dictionary{
+		words:   map[string]string{},
+		letters: map[string]string{},
+	}
+
+	for _, e := range entries {
+		if err := d.add(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// Validate checks that an entry can be compiled.
+func Validate(e datastructures.Pronunciation) error {
+	return (&Dictionary{words: map[string]string{}, letters: map[string]string{}}).add(e)
+}
+
+func (d *Dictionary) add(e datastructures.Pronunciation) error {
+	pattern := strings.ToLower(strings.TrimSpace(e.Pattern))
+	replacement := strings.TrimSpace(e.Replacement)
+	if pattern == "" {
+		return fmt.Errorf("%w: empty pattern", ErrInvalidEntry)
+	}
+
+	switch e.Type {
+	case datastructures.PronunciationTypeWord, datastructures.PronunciationTypeLetters:
+		if strings.ContainsAny(pattern, " \t\n") {
+			return fmt.Errorf("%w: %s is not a single word", ErrInvalidEntry, pattern)
+		}
+		replacement = strings.ToLower(replacement)
+		if replacement == "" {
+			return fmt.Errorf("%w: empty replacement", ErrInvalidEntry)
+		}
+		// a word replaces the letters of an older entry and the other way around.
+		delete(d.words, pattern)
+		delete(d.letters, pattern)
+		if e.Type == datastructures.PronunciationTypeWord {
+			d.words[pattern] = replacement
+		} else {
+			d.letters[pattern] = replacement
+		}
+	case datastructures.PronunciationTypeRegex:
+		re, err := regexp.Compile(e.Pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidEntry, err.Error())
+		}
+		d.regexes = append(d.regexes, regexEntry{re: re, replacement: replacement})
+	default:
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidEntry, e.Type)
+	}
+
+	return nil
+}
+
+// Word returns how a word is read.
+func (d *Dictionary) Word(word string) (string, bool) {
+	v, ok := d.words[word]
+	return v, ok
+}
+
+// Letters returns what a word is spelled as.
+func (d *Dictionary) Letters(word string) (string, bool) {
+	v, ok := d.letters[word]
+	return v, ok
+}
+
+// Replace applies the regex entries in the order they were added.
+func (d *Dictionary) Replace(text string) string {
+	for _, e := range d.regexes {
+		text = e.re.ReplaceAllString(text, e.replacement)
+	}
+	return text
+}
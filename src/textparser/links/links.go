@@ -0,0 +1,89 @@
+package links
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// PolicyRead reads links and emails as "link to example dot com".
+	PolicyRead = "read"
+	// PolicyDrop removes links and emails.
+	PolicyDrop = "drop"
+)
+
+var (
+	emailRe   = regexp.MustCompile(`(?i)\b[\w.+-]+@((?:[a-z0-9-]+\.)+[a-z]{2,})\b`)
+	urlRe     = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s]+|\b(?:[a-z0-9-]+\.)+(?:com|net|org|io|tv|gg|co|uk|de|se|no|dk|fi|ru|me|ly|be|app|dev|xyz|info|edu|gov)\b(?:[/?#][^\s]*)?`)
+	hostRe    = regexp.MustCompile(`(?i)^(?:https?://)?([^/?#:\s]+)`)
+	mentionRe = regexp.MustCompile(`(^|[\s(])@(\w+)`)
+	hashtagRe = regexp.MustCompile(`(^|[\s(])#(\w+)`)
+	camelRe   = regexp.MustCompile(`([a-z])([A-Z])`)
+	digitRe   = regexp.MustCompile(`([a-zA-Z])(\d)|(\d)([a-zA-Z])`)
+)
+
+// Normalize reads links, emails, mentions and hashtags, it has to run before the text is lowercased
+// so camel case in mentions and hashtags can be split into words.
+func Normalize(text string, policy string) string {
+	text = emailRe.ReplaceAllStringFunc(text, func(s string) string {
+		if policy == PolicyDrop {
+			return " "
+		}
+		return " email to " + domain(emailRe.FindStringSubmatch(s)[1]) + " "
+	})
+
+	text = urlRe.ReplaceAllStringFunc(text, func(s string) string {
+		if policy == PolicyDrop {
+			return " "
+		}
+		m := hostRe.FindStringSubmatch(s)
+		if m == nil {
+			return " link "
+		}
+		return " link to " + domain(m[1]) + " "
+	})
+
+	text = mentionRe.ReplaceAllStringFunc(text, func(s string) string {
+		m := mentionRe.FindStringSubmatch(s)
+		return m[1] + words(m[2])
+	})
+
+	text = hashtagRe.ReplaceAllStringFunc(text, func(s string) string {
+		m := hashtagRe.FindStringSubmatch(s)
+		return m[1] + "hashtag " + words(m[2])
+	})
+
+	return text
+}
+
+// domain reads the registered part of a host, subdomains of tracking links are never read.
+func domain(host string) string {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+	if len(labels) > 0 && labels[0] == "www" {
+		labels = labels[1:]
+	}
+
+	keep := 2
+	if l := len(labels); l > 2 && len(labels[l-1]) == 2 && len(labels[l-2]) <= 3 {
+		// second level domains like co dot uk.
+		keep = 3
+	}
+	if len(labels) > keep {
+		labels = labels[len(labels)-keep:]
+	}
+
+	for i, v := range labels {
+		labels[i] = strings.ReplaceAll(v, "-", " dash ")
+	}
+
+	return strings.Join(labels, " dot ")
+}
+
+// words splits a name like "Cool_Streamer99" into "Cool Streamer 99".
+func words(name string) string {
+	name = strings.ReplaceAll(name, "_", " ")
+	name = camelRe.ReplaceAllString(name, "$1 $2")
+	name = digitRe.ReplaceAllString(name, "$1$3 $2$4")
+
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,139 @@
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
+)
+
+const (
+	TagPause    = "pause"
+	TagFast     = "fast"
+	TagSlow     = "slow"
+	TagLoud     = "loud"
+	TagQuiet    = "quiet"
+	TagWhisper  = "whisper"
+	TagEmphasis = "emphasis"
+)
+
+// Tags are all the tags which can be allowed in the alert rules.
+var Tags = []string{TagPause, TagFast, TagSlow, TagLoud, TagQuiet, TagWhisper, TagEmphasis}
+
+const (
+	// MaxPause is the longest a single pause can be.
+	MaxPause = time.Second * 5
+	// MaxTotalPause is the longest all pauses of a message can be together.
+	MaxTotalPause = time.Second * 10
+)
+
+var tagRe = regexp.MustCompile(`\[\s*(/?)\s*(pause|fast|slow|loud|quiet|whisper|emphasis)(?:\s+(\d+(?:\.\d+)?)\s*(ms|s)?)?\s*\]`)
+
+// styles are the changes the tags make, a zero value keeps what was set before.
+var styles = map[string]parts.Style{
+	TagFast:     {Pace: 1.3},
+	TagSlow:     {Pace: 0.75},
+	TagLoud:     {Volume: 1.5},
+	TagQuiet:    {Volume: 0.6},
+	TagWhisper:  {Pace: 0.9, Volume: 0.4},
+	TagEmphasis: {Pace: 0.85, Volume: 1.3},
+}
+
+// NormalizeMarkup turns tags like [pause 2s] and [fast]...[/fast] into pauses and part styles,
+// tags which are not allowed are removed. Styles last until they are closed or the message ends.
+func NormalizeMarkup(pts []parts.VoicePart, allowed []string) []parts.VoicePart {
+	allow := map[string]bool{}
+	for _, v := range allowed {
+		allow[v] = true
+	}
+
+	style := parts.Style{}
+	total := time.Duration(0)
+	rPts := []parts.VoicePart{}
+	for _, v := range pts {
+		if v.Type != parts.PartTypeRaw {
+			v.Style = style
+			rPts = append(rPts, v)
+			continue
+		}
+
+		text := v.Value
+		for {
+			idx := tagRe.FindStringSubmatchIndex(text)
+			if idx == nil {
+				break
+			}
+			match := tagRe.FindStringSubmatch(text)
+			closing, tag := match[1] == "/", match[2]
+
+			if preText := strings.TrimSpace(text[:idx[0]]); preText != "" {
+				rPts = append(rPts, parts.VoicePart{Value: preText, Type: parts.PartTypeRaw, Style: style})
+			}
+			text = text[idx[1]:]
+
+			if !allow[tag] {
+				continue
+			}
+
+			if tag == TagPause {
+				if closing || len(rPts) == 0 {
+					continue
+				}
+				d := pause(match[3], match[4])
+				if total+d > MaxTotalPause {
+					d = MaxTotalPause - total
+				}
+				if d > 0 {
+					total += d
+					rPts = append(rPts, parts.VoicePart{Type: parts.PartTypePause, Pause: d})
+				}
+				continue
+			}
+
+			s := styles[tag]
+			if closing {
+				if s.Pace != 0 {
+					style.Pace = 0
+				}
+				if s.Volume != 0 {
+					style.Volume = 0
+				}
+				continue
+			}
+			if s.Pace != 0 {
+				style.Pace = s.Pace
+			}
+			if s.Volume != 0 {
+				style.Volume = s.Volume
+			}
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			rPts = append(rPts, parts.VoicePart{Value: text, Type: parts.PartTypeRaw, Style: style})
+		}
+	}
+
+	return rPts
+}
+
+// pause parses the length of a pause, a number without a unit is in seconds and no number is a second.
+func pause(number string, unit string) time.Duration {
+	if number == "" {
+		return time.Second
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+
+	d := time.Duration(n * float64(time.Second))
+	if unit == "ms" {
+		d = time.Duration(n * float64(time.Millisecond))
+	}
+	if d > MaxPause {
+		d = MaxPause
+	}
+
+	return d
+}
@@ -0,0 +1,278 @@
+package numbers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
+)
+
+var months = []string{"", "january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+
+var unitNames = map[string][2]string{
+	"kg":   {"kilogram", "kilograms"},
+	"g":    {"gram", "grams"},
+	"lb":   {"pound", "pounds"},
+	"lbs":  {"pound", "pounds"},
+	"km":   {"kilometer", "kilometers"},
+	"cm":   {"centimeter", "centimeters"},
+	"mm":   {"millimeter", "millimeters"},
+	"ft":   {"foot", "feet"},
+	"mi":   {"mile", "miles"},
+	"ms":   {"millisecond", "milliseconds"},
+	"s":    {"second", "seconds"},
+	"sec":  {"second", "seconds"},
+	"min":  {"minute", "minutes"},
+	"h":    {"hour", "hours"},
+	"hr":   {"hour", "hours"},
+	"hrs":  {"hour", "hours"},
+	"kmh":  {"kilometer per hour", "kilometers per hour"},
+	"km/h": {"kilometer per hour", "kilometers per hour"},
+	"mph":  {"mile per hour", "miles per hour"},
+	"kb":   {"kilobyte", "kilobytes"},
+	"mb":   {"megabyte", "megabytes"},
+	"gb":   {"gigabyte", "gigabytes"},
+	"tb":   {"terabyte", "terabytes"},
+	"hz":   {"hertz", "hertz"},
+	"ghz":  {"gigahertz", "gigahertz"},
+	"fps":  {"f p s", "f p s"},
+	"mmr":  {"m m r", "m m r"},
+}
+
+var (
+	isoDateRe   = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	slashDateRe = regexp.MustCompile(`\b(\d{1,2})[/.](\d{1,2})[/.](\d{4}|\d{2})\b`)
+	timeRe      = regexp.MustCompile(`\b(\d{1,2}):(\d{2})(?:\s*([ap])\.?m\b\.?)?`)
+	hourRe      = regexp.MustCompile(`\b(\d{1,2})\s*([ap])\.?m\b\.?`)
+	percentRe   = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	fractionRe  = regexp.MustCompile(`\b(\d+)/(\d+)\b`)
+	rangeRe     = regexp.MustCompile(`\b(\d+)\s*-\s*(\d+)\b`)
+	ordinalRe   = regexp.MustCompile(`\b(\d+)(st|nd|rd|th)\b`)
+	unitRe      = regexp.MustCompile(`\b(\d+(?:\.\d+)?)\s*(km/h|kmh|mph|kg|lbs|lb|km|cm|mm|ft|mi|ms|sec|min|hrs|hr|kb|mb|gb|tb|ghz|hz|fps|mmr|g|s|h)\b`)
+	yearRe      = regexp.MustCompile(`\b(1[1-9]\d\d|20\d\d)\b`)
+)
+
+// replace calls fn with the submatches of every match, a match is kept as is when fn returns false.
+func replace(re *regexp.Regexp, text string, fn func(m []string) (string, bool)) string {
+	return re.ReplaceAllStringFunc(text, func(s string) string {
+		if v, ok := fn(re.FindStringSubmatch(s)); ok {
+			return " " + v + " "
+		}
+		return s
+	})
+}
+
+// normalizeForms rewrites the numbers which have a spoken form other than the cardinal number.
+func normalizeForms(text string, partType parts.PartType) string {
+	text = replace(isoDateRe, text, func(m []string) (string, bool) {
+		return date(m[3], m[2], m[1])
+	})
+	text = replace(slashDateRe, text, func(m []string) (string, bool) {
+		day, month := m[1], m[2]
+		if d, _ := strconv.Atoi(day); d <= 12 {
+			if mo, _ := strconv.Atoi(month); mo > 12 {
+				// month first.
+				day, month = month, day
+			}
+		}
+		return date(day, month, m[3])
+	})
+	text = replace(timeRe, text, func(m []string) (string, bool) {
+		return clock(m[1], m[2], m[3])
+	})
+	text = replace(hourRe, text, func(m []string) (string, bool) {
+		return clock(m[1], "00", m[2])
+	})
+	text = replace(percentRe, text, func(m []string) (string, bool) {
+		return cardinal(m[1]) + " percent", true
+	})
+	text = replace(fractionRe, text, func(m []string) (string, bool) {
+		return fraction(m[1], m[2])
+	})
+	text = replace(rangeRe, text, func(m []string) (string, bool) {
+		a, errA := strconv.Atoi(m[1])
+		b, errB := strconv.Atoi(m[2])
+		if errA != nil || errB != nil {
+			return "", false
+		}
+		switch {
+		case a < b && partType == parts.PartTypeRaw && yearRe.MatchString(m[1]) && yearRe.MatchString(m[2]):
+			return year(m[1]) + " to " + year(m[2]), true
+		case a < b:
+			return cardinal(m[1]) + " to " + cardinal(m[2]), true
+		case a == b:
+			// a tied score.
+			return cardinal(m[1]) + " all", true
+		default:
+			// a score, the winner is read first.
+			return cardinal(m[1]) + " " + cardinal(m[2]), true
+		}
+	})
+	text = replace(ordinalRe, text, func(m []string) (string, bool) {
+		return ordinal(m[1]), true
+	})
+	text = replace(unitRe, text, func(m []string) (string, bool) {
+		name, ok := unitNames[m[2]]
+		if !ok {
+			return "", false
+		}
+		if m[1] == "1" {
+			return cardinal(m[1]) + " " + name[0], true
+		}
+		return cardinal(m[1]) + " " + name[1], true
+	})
+	if partType == parts.PartTypeRaw {
+		// amounts of money are never years.
+		text = replace(yearRe, text, func(m []string) (string, bool) {
+			return year(m[1]), true
+		})
+	}
+
+	return text
+}
+
+// cardinal reads a number with an optional decimal part.
+func cardinal(number string) string {
+	decimal := ""
+	if idx := strings.IndexByte(number, '.'); idx != -1 {
+		number, decimal = number[:idx], number[idx+1:]
+	}
+
+	if number == "" {
+		number = "0"
+	}
+
+	var out string
+	if number[0] == '0' {
+		out = strings.Join(numberDigits(number), " ")
+	} else {
+		out = normalizeNumber(number)
+	}
+	if decimal != "" {
+		out = fmt.Sprint(out, " point ", strings.Join(numberDigits(decimal), " "))
+	}
+
+	return out
+}
+
+var ordinalWords = map[string]string{
+	"one":    "first",
+	"two":    "second",
+	"three":  "third",
+	"five":   "fifth",
+	"eight":  "eighth",
+	"nine":   "ninth",
+	"twelve": "twelfth",
+}
+
+// ordinal reads a number as a position, 21 is twenty first.
+func ordinal(number string) string {
+	n, err := strconv.Atoi(number)
+	if err != nil || number[0] == '0' {
+		return cardinal(number)
+	}
+
+	words := strings.Split(normalizeNumber(strconv.Itoa(n)), " ")
+	last := strings.TrimSuffix(words[len(words)-1], ",")
+	switch {
+	case ordinalWords[last] != "":
+		last = ordinalWords[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	words[len(words)-1] = last
+
+	return strings.Join(words, " ")
+}
+
+// year reads a year the way it is spoken, 1999 is nineteen ninety nine.
+func year(number string) string {
+	y, err := strconv.Atoi(number)
+	if err != nil {
+		return cardinal(number)
+	}
+
+	hundreds, rest := y/100, y%100
+	switch {
+	case y == 2000:
+		return "two thousand"
+	case y > 2000 && y < 2010:
+		return "two thousand " + units[rest]
+	case rest == 0:
+		return convertNumber(hundreds) + " hundred"
+	case rest < 10:
+		return convertNumber(hundreds) + " oh " + units[rest]
+	default:
+		return convertNumber(hundreds) + " " + convertNumber(rest)
+	}
+}
+
+func date(day, month, yr string) (string, bool) {
+	d, errD := strconv.Atoi(day)
+	m, errM := strconv.Atoi(month)
+	if errD != nil || errM != nil || d < 1 || d > 31 || m < 1 || m > 12 {
+		return "", false
+	}
+
+	if len(yr) == 2 {
+		yr = "20" + yr
+	}
+
+	return fmt.Sprintf("%s %s %s", months[m], ordinal(strconv.Itoa(d)), year(yr)), true
+}
+
+func clock(hour, minute, meridiem string) (string, bool) {
+	h, errH := strconv.Atoi(hour)
+	m, errM := strconv.Atoi(minute)
+	if errH != nil || errM != nil || h > 23 || m > 59 || (meridiem != "" && (h == 0 || h > 12)) {
+		return "", false
+	}
+
+	out := cardinal(strconv.Itoa(h))
+	switch {
+	case m == 0 && meridiem == "":
+		out += " o clock"
+	case m == 0:
+	case m < 10:
+		out += " oh " + units[m]
+	default:
+		out += " " + convertNumber(m)
+	}
+	if meridiem != "" {
+		out += " " + meridiem + " m"
+	}
+
+	return out, true
+}
+
+var denominators = map[int][2]string{
+	2: {"half", "halves"},
+	4: {"quarter", "quarters"},
+}
+
+func fraction(numerator, denominator string) (string, bool) {
+	n, errN := strconv.Atoi(numerator)
+	d, errD := strconv.Atoi(denominator)
+	if errN != nil || errD != nil || d == 0 {
+		return "", false
+	}
+
+	if d > 10 {
+		return cardinal(numerator) + " over " + cardinal(denominator), true
+	}
+
+	name, ok := denominators[d]
+	if !ok {
+		o := ordinal(denominator)
+		name = [2]string{o, o + "s"}
+	}
+	if n == 1 {
+		return "one " + name[0], true
+	}
+
+	return cardinal(numerator) + " " + name[1], true
+}
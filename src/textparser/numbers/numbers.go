@@ -68,7 +68,7 @@ func NormalizeNumbers(pts []parts.VoicePart) []parts.VoicePart {
 			retParts = append(retParts, p)
 			continue
 		}
-		text := strings.TrimSpace(p.Value)
+		text := strings.TrimSpace(normalizeForms(p.Value, p.Type))
 		matches := re.FindAllStringSubmatch(text, -1)
 		p.Value = ""
 		for _, match := range matches {
@@ -122,9 +122,12 @@ func normalizeNumber(number string) string {
 		// this number is too big to read so we should read the digits.
 		parts = numberDigits(number)
 	} else {
-		parts = make([]string, l)
-		for i, v := range numbers {
-			parts[l-i-1] = fmt.Sprint(convertNumber(v), " ", dividers[i])
+		for i := l - 1; i >= 0; i-- {
+			// empty groups like the thousands in a million are not read.
+			if numbers[i] == 0 && l != 1 {
+				continue
+			}
+			parts = append(parts, strings.TrimSpace(fmt.Sprint(convertNumber(numbers[i]), " ", dividers[i])))
 		}
 	}
 	return strings.TrimSpace(strings.Join(parts, ", "))
@@ -171,7 +174,7 @@ func convertNumber(number int) string {
 		}
 		parts = append(parts, tens[n])
 	}
-	if number != 0 {
+	if number != 0 || len(parts) == 0 {
 		parts = append(parts, units[number])
 	}
 
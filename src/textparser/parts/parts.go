@@ -1,6 +1,10 @@
 package parts
 
-import "github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+import (
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+)
 
 type PartType int
 
@@ -8,6 +12,10 @@ const (
 	PartTypeCurrency PartType = iota
 	PartTypeRaw
 	PartTypeOverride
+	// PartTypePause is silence of VoicePart.Pause without any text.
+	PartTypePause
+	// PartTypeArpabet is a pronunciation like {HH AH0 L OW1} which is sent to the workers as it is.
+	PartTypeArpabet
 )
 
 type SpaceType int
@@ -35,6 +43,16 @@ type VoicePart struct {
 	Value string
 	Voice Voice
 	Type  PartType
+	Style Style
+	Pause time.Duration
+}
+
+// Style changes how a part is read, zero values keep the settings of the voice.
+type Style struct {
+	// Pace multiplies the pace of the voice.
+	Pace float64
+	// Volume multiplies the volume of the voice.
+	Volume float64
 }
 
 type Voice struct {
@@ -1,10 +1,10 @@
 package sentance
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/dictionary"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
 	"github.com/jdkato/prose/v2"
 )
@@ -14,7 +14,7 @@ var reSpace = regexp.MustCompile(`\s|-`)
 func SplitVoices(pts []parts.VoicePart, limit int) []parts.VoicePart {
 	rPts := []parts.VoicePart{}
 	for _, v := range pts {
-		if v.Voice.Type == parts.VoicePartTypeByte {
+		if v.Voice.Type == parts.VoicePartTypeByte || v.Type == parts.PartTypePause || v.Type == parts.PartTypeArpabet {
 			rPts = append(rPts, v)
 			continue
 		}
@@ -42,6 +42,7 @@ func SplitVoices(pts []parts.VoicePart, limit int) []parts.VoicePart {
 							rPts = append(rPts, parts.VoicePart{
 								Value: currentBuild,
 								Voice: v.Voice,
+								Style: v.Style,
 								VoicePartMeta: parts.VoicePartMeta{
 									Space: parts.SpaceTypeShortPause,
 								},
@@ -54,6 +55,7 @@ func SplitVoices(pts []parts.VoicePart, limit int) []parts.VoicePart {
 						rPts = append(rPts, parts.VoicePart{
 							Value: currentBuild,
 							Voice: v.Voice,
+							Style: v.Style,
 							VoicePartMeta: parts.VoicePartMeta{
 								Space: parts.SpaceTypeShortPause,
 							},
@@ -64,6 +66,7 @@ func SplitVoices(pts []parts.VoicePart, limit int) []parts.VoicePart {
 						rPts = append(rPts, parts.VoicePart{
 							Value: cm,
 							Voice: v.Voice,
+							Style: v.Style,
 							VoicePartMeta: parts.VoicePartMeta{
 								Space: parts.SpaceTypeLongPause,
 							},
@@ -72,6 +75,7 @@ func SplitVoices(pts []parts.VoicePart, limit int) []parts.VoicePart {
 						rPts = append(rPts, parts.VoicePart{
 							Value: cm,
 							Voice: v.Voice,
+							Style: v.Style,
 							VoicePartMeta: parts.VoicePartMeta{
 								Space: parts.SpaceTypeMediumPause,
 							},
@@ -85,43 +89,13 @@ func SplitVoices(pts []parts.VoicePart, limit int) []parts.VoicePart {
 	return rPts
 }
 
-var abr = map[*regexp.Regexp]string{}
-
-var rawAbr = map[string]string{
-	"mrs":  "misess",
-	"mr":   "mister",
-	"mt":   "mount",
-	"dr":   "doctor",
-	"st":   "saint",
-	"co":   "company",
-	"jr":   "junior",
-	"maj":  "major",
-	"gen":  "general",
-	"drs":  "doctors",
-	"rev":  "reverend",
-	"lt":   "lieutenant",
-	"hon":  "honorable",
-	"sgt":  "sergeant",
-	"capt": "captain",
-	"esq":  "esquire",
-	"ltd":  "limited",
-	"col":  "colonel",
-	"ft":   "fort",
-}
-
-func init() {
-	for k, v := range rawAbr {
-		abr[regexp.MustCompile(fmt.Sprintf(`\b%s\.?\b`, k))] = v
-	}
-}
-
-func FixAbbreviations(pts []parts.VoicePart) []parts.VoicePart {
+// FixAbbreviations applies the regex entries of the dictionary, which expand abbreviations like "mr" to "mister".
+func FixAbbreviations(pts []parts.VoicePart, dict *dictionary.Dictionary) []parts.VoicePart {
 	for i, v := range pts {
-		txt := v.Value
-		for re, repl := range abr {
-			txt = re.ReplaceAllString(txt, repl)
+		if v.Type == parts.PartTypePause || v.Type == parts.PartTypeArpabet {
+			continue
 		}
-		v.Value = txt
+		v.Value = dict.Replace(v.Value)
 		pts[i] = v
 	}
 
@@ -0,0 +1,115 @@
+package spam
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxNgram is the longest group of words which is checked for repetition.
+const maxNgram = 4
+
+// Limits are how often something can repeat in a row before the rest is dropped, limits below 1 are not applied.
+type Limits struct {
+	// Chars limits runs of the same letter or symbol, digits are never compressed.
+	Chars int
+	// Words limits the same word and words made of one repeated piece like "hahahaha".
+	Words int
+	// Ngrams limits groups of two or more words.
+	Ngrams int
+}
+
+// Compress collapses repeated characters, words and groups of words to the limits.
+func Compress(text string, limits Limits) string {
+	if limits.Chars > 0 {
+		text = compressChars(text, limits.Chars)
+	}
+
+	tokens := strings.Fields(text)
+	if limits.Words > 0 {
+		for i, v := range tokens {
+			tokens[i] = compressPeriod(v, limits.Words)
+		}
+		tokens = strings.Fields(strings.Join(tokens, " "))
+		tokens = compressNgrams(tokens, 1, limits.Words)
+	}
+	if limits.Ngrams > 0 {
+		for n := 2; n <= maxNgram; n++ {
+			tokens = compressNgrams(tokens, n, limits.Ngrams)
+		}
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+func compressChars(text string, limit int) string {
+	sb := strings.Builder{}
+	var last rune
+	count := 0
+	for _, r := range text {
+		if r == last {
+			count++
+		} else {
+			last = r
+			count = 1
+		}
+		if count > limit && !unicode.IsDigit(r) && !unicode.IsSpace(r) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// compressPeriod splits a word made of one repeated piece like "kekwkekwkekw" into at most limit pieces.
+func compressPeriod(word string, limit int) string {
+	runes := []rune(word)
+	l := len(runes)
+	for p := 2; p <= l/2; p++ {
+		if l%p != 0 || l/p <= limit {
+			continue
+		}
+		piece := string(runes[:p])
+		if strings.Repeat(piece, l/p) != word || strings.IndexFunc(piece, unicode.IsDigit) != -1 {
+			continue
+		}
+
+		pieces := make([]string, limit)
+		for i := range pieces {
+			pieces[i] = piece
+		}
+		return strings.Join(pieces, " ")
+	}
+
+	return word
+}
+
+// compressNgrams drops the groups of n words which repeat more than limit times in a row.
+func compressNgrams(tokens []string, n int, limit int) []string {
+	ret := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); {
+		reps := 1
+		for i+(reps+1)*n <= len(tokens) && equal(tokens[i:i+n], tokens[i+reps*n:i+(reps+1)*n]) {
+			reps++
+		}
+		if reps > limit {
+			ret = append(ret, tokens[i:i+limit*n]...)
+			i += reps * n
+			continue
+		}
+		ret = append(ret, tokens[i])
+		i++
+	}
+
+	return ret
+}
+
+// equal compares words without the punctuation around them, so "lol, lol, lol" is a repetition.
+func equal(a, b []string) bool {
+	for i := range a {
+		if strings.TrimFunc(a[i], unicode.IsPunct) != strings.TrimFunc(b[i], unicode.IsPunct) {
+			return false
+		}
+	}
+	return true
+}
@@ -12,6 +12,9 @@ var spaceFix = regexp.MustCompile(`\s+`)
 
 func NormalizeCharacters(pts []parts.VoicePart) []parts.VoicePart {
 	for i := range pts {
+		if pts[i].Type == parts.PartTypeArpabet {
+			continue
+		}
 		pts[i].Value = spaceFix.ReplaceAllString(re.ReplaceAllString(reSpace.ReplaceAllString(pts[i].Value, " "), ""), " ")
 	}
 
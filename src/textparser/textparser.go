@@ -1,20 +1,28 @@
 package textparser
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/admiralbulldogtv/yappercontroller/src/alerts"
 	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/arpabet"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/blacklist"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/currency"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/dictionary"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/links"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/markup"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/numbers"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/override"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/sentance"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/spam"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/strip"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/translit"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/voice"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/words"
-	"github.com/admiralbulldogtv/yappercontroller/src/utils"
 	"github.com/gobuffalo/packr/v2"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -24,10 +32,27 @@ var json = jsoniter.ConfigCompatibleWithStandardLibrary
 var Voices []parts.Voice
 var VoicesMap map[string]parts.Voice = map[string]parts.Voice{}
 
+// Sounds are the sound bytes which can be used in a message as (name).
+var Sounds []parts.Voice
+var SoundsMap = map[string]parts.Voice{}
+
+// BleepSound can always be used so blacklisted words can be bleeped.
+const BleepSound = "bleep"
+
 var (
-	ErrBlacklisted = fmt.Errorf("blacklisted phrases")
+	ErrBlacklisted = blacklist.ErrBlacklisted
+	ErrUnreadable  = fmt.Errorf("unreadable message")
 )
 
+// IsRejected reports if a message was rejected because of what it says, retrying it cannot succeed.
+func IsRejected(err error) bool {
+	var invalid *arpabet.InvalidError
+	return err == ErrBlacklisted || err == ErrUnreadable || errors.As(err, &invalid)
+}
+
+// UnreadableText is read instead of a message which is mostly unreadable.
+const UnreadableText = "unreadable message"
+
 func init() {
 	box := packr.New("textparser-static", "./static")
 	data, err := box.Find("configs.json")
@@ -53,30 +78,89 @@ func init() {
 	for _, v := range Voices {
 		VoicesMap[v.Name] = v
 	}
+
+	for name := range alerts.Sounds {
+		sound := parts.Voice{
+			Name: name,
+			Type: parts.VoicePartTypeByte,
+		}
+		Sounds = append(Sounds, sound)
+		SoundsMap[name] = sound
+	}
+	sort.Slice(Sounds, func(i, j int) bool {
+		return Sounds[i].Name < Sounds[j].Name
+	})
 }
 
-func Process(text string, currentVoice parts.Voice, validVoices []parts.Voice, maxVoiceSwaps int) ([]parts.VoicePart, error) {
-	text = strings.ToLower(text)
-	for _, re := range blacklisted {
-		if re.Match(utils.S2B(text)) {
-			return nil, ErrBlacklisted
+// Options configure how a message is processed.
+type Options struct {
+	Voice         parts.Voice
+	ValidVoices   []parts.Voice
+	MaxVoiceSwaps int
+	// Blacklist is checked against the raw text and against the normalized output.
+	Blacklist blacklist.List
+	// MaxUnreadable is the ratio of letters which cannot be read before the message is treated as unreadable,
+	// it is then either rejected or replaced with UnreadableText.
+	MaxUnreadable    float64
+	RejectUnreadable bool
+	// Links is the links policy for urls and emails, links.PolicyRead or links.PolicyDrop.
+	Links string
+	// Spam limits how often characters, words and groups of words can repeat.
+	Spam spam.Limits
+	// Markup are the markup tags which are applied, other tags are removed.
+	Markup []string
+	// Dictionary is how words are pronounced, the built in dictionary is used when it is nil.
+	Dictionary *dictionary.Dictionary
+}
+
+func Process(text string, opts Options) ([]parts.VoicePart, error) {
+	// links are read from the raw text, transliteration would rewrite the & and ? of their query strings.
+	text = links.Normalize(text, opts.Links)
+
+	text, unreadable := translit.Transliterate(text)
+	if opts.MaxUnreadable > 0 && unreadable > opts.MaxUnreadable {
+		if opts.RejectUnreadable {
+			return nil, ErrUnreadable
 		}
+		text = UnreadableText
+	}
+
+	text = strings.ToLower(text)
+	text = spam.Compress(text, opts.Spam)
+	text, err := opts.Blacklist.Apply(text)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := opts.Dictionary
+	if dict == nil {
+		dict = dictionary.Default
 	}
 
 	stat := override.NormalizeOverride([]parts.VoicePart{{Type: parts.PartTypeRaw, Value: text}})
-	stat = voice.NormalizeVoices(stat, currentVoice, validVoices, maxVoiceSwaps)
+	if stat, err = arpabet.NormalizeArpabet(stat); err != nil {
+		return nil, err
+	}
+	stat = markup.NormalizeMarkup(stat, opts.Markup)
+	stat = voice.NormalizeVoices(stat, opts.Voice, opts.ValidVoices, opts.MaxVoiceSwaps)
 	stat = currency.NormalizeCurrency(stat)
 	stat = numbers.NormalizeNumbers(stat)
-	stat = sentance.FixAbbreviations(stat)
-	stat = words.NormalizeWords(stat)
+	stat = sentance.FixAbbreviations(stat, dict)
+	stat = words.NormalizeWords(stat, dict)
 
 	stat = sentance.SplitVoices(stat, 250)
 	stat = strip.NormalizeCharacters(stat)
 
-	return stat, nil
-}
+	// obfuscated phrases only show up once everything has been spelled out.
+	for i, v := range stat {
+		if v.Type == parts.PartTypeArpabet {
+			continue
+		}
+		if stat[i].Value, err = opts.Blacklist.Apply(v.Value); err != nil {
+			return nil, err
+		}
+	}
+	stat = strip.NormalizeCharacters(stat)
 
-var blacklisted = []*regexp.Regexp{
-	regexp.MustCompile(`\bgor(?:gc|p)_?s?\b`),
-	regexp.MustCompile(`\bknee\b`),
+	return stat, nil
 }
@@ -0,0 +1,113 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// letters are the characters which do not decompose into ascii.
+var letters = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'þ': "th", 'Þ': "TH",
+	'ł': "l", 'Ł': "L",
+	'ı': "i", 'ŀ': "l",
+	'ħ': "h", 'Ħ': "H",
+	'ŋ': "ng", 'Ŋ': "NG",
+	'ſ': "s", 'ĸ': "k",
+}
+
+// symbols are replaced with something which the later stages understand.
+var symbols = map[rune]string{
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'",
+	'“': `"`, '”': `"`, '„': `"`, '‟': `"`, '″': `"`, '«': `"`, '»': `"`,
+	'–': "-", '—': "-", '―': "-", '‐': "-", '‑': "-", '−': "-",
+	'…': "...", '•': ",", '·': ",",
+	'×': " times ", '÷': " divided by ",
+	'&': " and ",
+	'°': " degrees ", '№': " number ",
+	'©': " copyright ", '®': " registered ",
+	'♥': " love ", '❤': " love ",
+}
+
+// keep are characters which are not ascii but are read by a later stage.
+var keep = map[rune]bool{
+	'€': true, '£': true, '¥': true, '₹': true, '₽': true,
+	'₩': true, '₺': true, '₴': true, '₱': true, '₪': true,
+}
+
+// Transliterate maps accented latin, fullwidth and styled letters and common symbols to ascii.
+// It returns the text and how much of the letters in it could not be read, between 0 and 1.
+func Transliterate(text string) (string, float64) {
+	sb := strings.Builder{}
+	total, unreadable := 0, 0
+	for _, r := range text {
+		if r < unicode.MaxASCII {
+			if unicode.IsLetter(r) {
+				total++
+			}
+			if v, ok := symbols[r]; ok {
+				sb.WriteString(v)
+			} else {
+				sb.WriteRune(r)
+			}
+			continue
+		}
+		if keep[r] {
+			sb.WriteRune(r)
+			continue
+		}
+		if v, ok := symbols[r]; ok {
+			sb.WriteString(v)
+			continue
+		}
+		if v, ok := letters[r]; ok {
+			total++
+			sb.WriteString(v)
+			continue
+		}
+		if unicode.IsSpace(r) {
+			sb.WriteByte(' ')
+			continue
+		}
+
+		// compatibility decomposition turns fullwidth, mathematical and circled letters into plain ones
+		// and splits accents from their letter.
+		found := false
+		for _, d := range norm.NFKD.String(string(r)) {
+			if unicode.Is(unicode.Mn, d) {
+				continue
+			}
+			if d < unicode.MaxASCII {
+				sb.WriteRune(d)
+				found = true
+			} else if v, ok := letters[d]; ok {
+				sb.WriteString(v)
+				found = true
+			}
+		}
+
+		if unicode.IsLetter(r) {
+			total++
+			if !found {
+				unreadable++
+				sb.WriteByte(' ')
+			}
+		} else if !found {
+			// emotes and other symbols are not read out.
+			sb.WriteByte(' ')
+		}
+	}
+
+	if total == 0 {
+		return sb.String(), 0
+	}
+
+	return sb.String(), float64(unreadable) / float64(total)
+}
@@ -2,6 +2,7 @@ package voice
 
 import (
 	"strings"
+	"unicode"
 
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
 )
@@ -10,56 +11,87 @@ func NormalizeVoices(pts []parts.VoicePart, currentVoice parts.Voice, validVoice
 	returnPts := []parts.VoicePart{}
 
 	for _, part := range pts {
+		if part.Type == parts.PartTypePause {
+			returnPts = append(returnPts, part)
+			continue
+		}
 		currentBuild := []string{}
 		splits := strings.Split(part.Value, " ")
-		for _, v := range splits {
-			found := false
-			for _, voice := range validVoices {
-				switch voice.Type {
-				// case parts.VoicePartTypeByte:
-				// 	if strings.HasPrefix(v, "("+voice.Name+")") {
-				// 		if len(currentBuild) != 0 {
-				// 			txt := strings.TrimSpace(strings.Join(currentBuild, " "))
-				// 			if len(txt) != 0 {
-				// 				returnPts = append(returnPts, parts.VoicePart{Value: txt, Voice: currentVoice, Type: part.Type})
-				// 			}
-				// 			currentBuild = []string{}
-				// 		}
-				// 		returnPts = append(returnPts, parts.VoicePart{Value: "", Voice: voice, Type: part.Type})
-				// 		found = true
-				// 	}
-				case parts.VoicePartTypeReader:
-					if strings.HasPrefix(v, voice.Name+":") {
-						if voice != currentVoice {
-							if len(returnPts) < maxVoiceSwaps {
-								if len(currentBuild) != 0 {
-									txt := strings.TrimSpace(strings.Join(currentBuild, " "))
-									if len(txt) != 0 {
-										returnPts = append(returnPts, parts.VoicePart{Value: txt, Voice: currentVoice, Type: part.Type})
+		for _, word := range splits {
+			// adjacent sound bytes like (airhorn)(bleep) are spliced one after another.
+			for _, v := range splitSounds(word, validVoices) {
+				found := false
+				for _, voice := range validVoices {
+					switch voice.Type {
+					case parts.VoicePartTypeByte:
+						// sound bytes count as a swap, once there are too many they are read as text.
+						if v == "("+voice.Name+")" && len(returnPts) < maxVoiceSwaps {
+							if len(currentBuild) != 0 {
+								txt := strings.TrimSpace(strings.Join(currentBuild, " "))
+								if len(txt) != 0 {
+									returnPts = append(returnPts, parts.VoicePart{Value: txt, Voice: currentVoice, Type: part.Type, Style: part.Style})
+								}
+								currentBuild = []string{}
+							}
+							returnPts = append(returnPts, parts.VoicePart{Value: "", Voice: voice, Type: part.Type})
+							found = true
+						}
+					case parts.VoicePartTypeReader:
+						if strings.HasPrefix(v, voice.Name+":") {
+							if voice != currentVoice {
+								if len(returnPts) < maxVoiceSwaps {
+									if len(currentBuild) != 0 {
+										txt := strings.TrimSpace(strings.Join(currentBuild, " "))
+										if len(txt) != 0 {
+											returnPts = append(returnPts, parts.VoicePart{Value: txt, Voice: currentVoice, Type: part.Type, Style: part.Style})
+										}
+										currentBuild = []string{}
 									}
-									currentBuild = []string{}
+									currentVoice = voice
+								} else {
+									v = "." + strings.TrimPrefix(v, voice.Name+":")
 								}
-								currentVoice = voice
-							} else {
-								v = "." + strings.TrimPrefix(v, voice.Name+":")
 							}
+							currentBuild = append(currentBuild, strings.TrimPrefix(v, voice.Name+":"))
+							found = true
 						}
-						currentBuild = append(currentBuild, strings.TrimPrefix(v, voice.Name+":"))
-						found = true
+					}
+					if found {
+						break
 					}
 				}
-				if found {
-					break
+				if !found {
+					currentBuild = append(currentBuild, v)
 				}
 			}
-			if !found {
-				currentBuild = append(currentBuild, v)
-			}
 		}
 		if len(currentBuild) != 0 {
-			returnPts = append(returnPts, parts.VoicePart{Value: strings.TrimSpace(strings.Join(currentBuild, " ")), Voice: currentVoice, Type: part.Type})
+			returnPts = append(returnPts, parts.VoicePart{Value: strings.TrimSpace(strings.Join(currentBuild, " ")), Voice: currentVoice, Type: part.Type, Style: part.Style})
 		}
 	}
 
 	return returnPts
 }
+
+// splitSounds splits the sound bytes off the start of a word, "(airhorn)(bleep)yo" becomes "(airhorn)", "(bleep)", "yo".
+// Punctuation which is left after the sound bytes is dropped.
+func splitSounds(word string, validVoices []parts.Voice) []string {
+	out := []string{}
+	for matched := true; matched; {
+		matched = false
+		for _, voice := range validVoices {
+			tag := "(" + voice.Name + ")"
+			if voice.Type == parts.VoicePartTypeByte && strings.HasPrefix(word, tag) {
+				out = append(out, tag)
+				word = word[len(tag):]
+				matched = true
+				break
+			}
+		}
+	}
+	if len(out) == 0 || strings.TrimFunc(word, unicode.IsPunct) != "" {
+		out = append(out, word)
+	}
+
+	return out
+}
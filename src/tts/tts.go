@@ -2,19 +2,22 @@ package tts
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"os"
-	"os/exec"
-	"path"
 	"sync"
 	"time"
 
+	"github.com/admiralbulldogtv/yappercontroller/src/alerts"
+	"github.com/admiralbulldogtv/yappercontroller/src/audio"
 	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
 	"github.com/admiralbulldogtv/yappercontroller/src/global"
 	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser"
 	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/spam"
 	"github.com/admiralbulldogtv/yappercontroller/src/utils"
 	"github.com/gobuffalo/packr/v2"
 	"github.com/google/uuid"
@@ -26,11 +29,11 @@ import (
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
 type Request struct {
-	Jid           string      `json:"jid"`
-	Event         int         `json:"event"`
-	ResponseEvent string      `json:"response_event"`
-	Payload       interface{} `json:"payload"`
-	Mode          int32       `json:"mode"`
+	Jid            string      `json:"jid"`
+	Event          int         `json:"event"`
+	ResponseStream string      `json:"response_stream"`
+	Payload        interface{} `json:"payload"`
+	Mode           int32       `json:"mode"`
 }
 
 type GenerateChangePayload struct {
@@ -67,31 +70,60 @@ type Response struct {
 	Time          float64                `json:"time"`
 }
 
+// GenerateChangeResponse is the audio of a job. Workers also send a "length", it is ignored on purpose:
+// segments are timed from the decoded audio, which is exact and also covers cached segments a worker never generated.
 type GenerateChangeResponse struct {
-	Data    string  `json:"data"`
-	Length  float64 `json:"length"`
-	Speaker string  `json:"speaker"`
+	Data    string `json:"data"`
+	Speaker string `json:"speaker"`
 }
 
 type ttsInstance struct {
-	gCtx        global.Context
-	mp          map[string]chan string
-	setKey      string
-	outputEvent string
-	mtx         sync.Mutex
-	cb          map[string]chan Response
+	gCtx         global.Context
+	mp           map[string]chan string
+	outputStream string
+	consumer     string
+	mtx          sync.Mutex
+	cb           map[string]chan Response
+}
+
+// resultGroup is the consumer group the controller reads its result stream with.
+const resultGroup = "controllers"
+
+// claimIdle is how long a result has to be pending before it is claimed from a previous run of the controller.
+const claimIdle = 30 * time.Second
+
+var (
+	// ErrNoAudio is returned when not a single segment of a message could be generated.
+	ErrNoAudio = fmt.Errorf("no tts worker answered")
+	// ErrNoWorker is returned when no live worker has loaded a voice of a message.
+	ErrNoWorker = fmt.Errorf("no tts worker available")
+)
+
+// job is a request for a worker and what the worker needs to generate it.
+type job struct {
+	data string
+	req  datastructures.WorkerRequirements
+}
+
+// entry is a job added to the stream of a worker.
+type entry struct {
+	stream string
+	id     string
 }
 
 type respHelper struct {
 	IdxMap map[int]parts.SpaceType
 	Jid    string
+	Key    string
 	Resp   Response
 	Voice  parts.Voice
+	Audio  *audio.Buffer
 }
 
-var (
-	soundMap = map[string][]byte{}
-)
+// outputFormat is the format of the generated audio, the format of the pause assets.
+var outputFormat = audio.Format{SampleRate: 22050, Channels: 1, BitsPerSample: 16}
+
+var soundMap = map[string]*audio.Buffer{}
 
 func init() {
 	box := packr.New("tts-assets", "./assets/")
@@ -101,17 +133,62 @@ func init() {
 		if err != nil {
 			panic(err)
 		}
-		soundMap[v] = sp
+		if soundMap[v], err = audio.Decode(sp); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// cacheKey hashes everything a segment is generated from, identical segments of any request share the key.
+func cacheKey(mode int32, payload GenerateChangePayload) string {
+	data, _ := json.Marshal(struct {
+		Mode    int32                 `json:"mode"`
+		Payload GenerateChangePayload `json:"payload"`
+	}{mode, payload})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// styled applies a markup multiplier to a setting of a voice.
+func styled(value float64, multiplier float64) float64 {
+	if multiplier == 0 {
+		return value
+	}
+	if value == 0 {
+		value = 1
 	}
+	return value * multiplier
 }
 
-func NewInstance(ctx global.Context, setKey, outputEvent string) (instance.TTS, error) {
+func NewInstance(ctx global.Context) (instance.TTS, error) {
+	cfg := ctx.Config().Redis
+	pick := func(v, d string) string {
+		if v == "" {
+			return d
+		}
+		return v
+	}
+
+	controllerID := cfg.ControllerID
+	if controllerID == "" {
+		controllerID, _ = os.Hostname()
+	}
+	if controllerID == "" {
+		id, _ := uuid.NewRandom()
+		controllerID = id.String()
+	}
+
 	inst := &ttsInstance{
-		gCtx:        ctx,
-		mp:          make(map[string]chan string),
-		setKey:      setKey,
-		outputEvent: outputEvent,
-		cb:          make(map[string]chan Response),
+		gCtx:         ctx,
+		mp:           make(map[string]chan string),
+		outputStream: fmt.Sprintf("%s:%s", pick(cfg.OutputStream, "tts:results"), controllerID),
+		consumer:     controllerID,
+		cb:           make(map[string]chan Response),
+	}
+
+	if err := ctx.Inst().Redis.XGroupCreate(ctx, inst.outputStream, resultGroup); err != nil {
+		return nil, err
 	}
 
 	go inst.process()
@@ -119,48 +196,249 @@ func NewInstance(ctx global.Context, setKey, outputEvent string) (instance.TTS,
 	return inst, nil
 }
 
+// process reads the result stream of the controller. Results which were delivered to a previous run of the
+// controller but never acknowledged are claimed first, nobody waits for them anymore so they are dropped.
 func (inst *ttsInstance) process() {
-	ch := make(chan string)
-	inst.gCtx.Inst().Redis.Subscribe(context.Background(), ch, inst.outputEvent)
-	var (
-		resp Response
-		err  error
-	)
-	for msg := range ch {
-		if err = json.UnmarshalFromString(msg, &resp); err != nil {
-			panic(err)
+	ctx := context.Background()
+	redis := inst.gCtx.Inst().Redis
+
+	for start := "0-0"; ; {
+		msgs, next, err := redis.XAutoClaim(ctx, inst.outputStream, resultGroup, inst.consumer, claimIdle, start, 100)
+		if err != nil {
+			logrus.WithError(err).Error("failed to claim pending tts results")
+			break
 		}
-		inst.mtx.Lock()
-		if v, ok := inst.cb[resp.Jid]; ok {
-			v <- resp
-			delete(inst.cb, resp.Jid)
+		inst.ack(ctx, msgs)
+		if next == "0-0" || len(msgs) == 0 {
+			break
+		}
+		start = next
+	}
+
+	var resp Response
+	for {
+		msgs, err := redis.XReadGroup(ctx, inst.outputStream, resultGroup, inst.consumer, 100, 5*time.Second)
+		if err != nil {
+			logrus.WithError(err).Error("failed to read tts results")
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, msg := range msgs {
+			data, _ := msg.Values["data"].(string)
+			if err = json.UnmarshalFromString(data, &resp); err != nil {
+				logrus.WithError(err).WithField("id", msg.ID).Error("bad response from tts worker")
+				continue
+			}
+			inst.mtx.Lock()
+			if v, ok := inst.cb[resp.Jid]; ok {
+				v <- resp
+				delete(inst.cb, resp.Jid)
+			}
+			inst.mtx.Unlock()
+		}
+		inst.ack(ctx, msgs)
+	}
+}
+
+// ack acknowledges and removes results from the result stream.
+func (inst *ttsInstance) ack(ctx context.Context, msgs []instance.StreamMessage) {
+	if len(msgs) == 0 {
+		return
+	}
+
+	ids := make([]string, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+	if err := inst.gCtx.Inst().Redis.XAck(ctx, inst.outputStream, resultGroup, ids...); err != nil {
+		logrus.WithError(err).Error("failed to acknowledge tts results")
+	}
+	if err := inst.gCtx.Inst().Redis.XDel(ctx, inst.outputStream, ids...); err != nil {
+		logrus.WithError(err).Error("failed to remove tts results")
+	}
+}
+
+// await submits the jobs and waits for the workers to answer them. Jobs which are not answered in time are
+// submitted again until they run out of retries, they are then left without audio. When the context is
+// cancelled the stream entries of the jobs which have not been answered are withdrawn.
+func (inst *ttsInstance) await(ctx context.Context, jobs map[string]job, cb chan Response, results map[string]*respHelper) error {
+	cfg := inst.gCtx.Config().TTS
+	timeout := time.Duration(cfg.JobTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	redis := inst.gCtx.Inst().Redis
+	attempts := map[string]int{}
+	deadlines := map[string]time.Time{}
+	entries := map[string][]entry{}
+	submit := func(jid string) error {
+		attempts[jid]++
+		deadlines[jid] = time.Now().Add(timeout)
+		if !inst.gCtx.Inst().Workers.Available(jobs[jid].req) {
+			return fmt.Errorf("%w: %s", ErrNoWorker, jobs[jid].req.Voice)
+		}
+		stream, err := inst.gCtx.Inst().Workers.Stream(ctx, jobs[jid].req)
+		if err != nil {
+			return err
+		}
+		id, err := redis.XAdd(ctx, stream, map[string]interface{}{"data": jobs[jid].data})
+		if err != nil {
+			return err
+		}
+		entries[jid] = append(entries[jid], entry{stream: stream, id: id})
+		return nil
+	}
+	withdraw := func(jid string) {
+		delete(attempts, jid)
+		// the request context might be done already.
+		for _, e := range entries[jid] {
+			if err := redis.XDel(inst.gCtx, e.stream, e.id); err != nil {
+				logrus.WithError(err).Error("failed to withdraw tts job")
+			}
+		}
+	}
+
+	for jid := range jobs {
+		if err := submit(jid); err != nil {
+			for jid := range attempts {
+				withdraw(jid)
+			}
+			return err
+		}
+	}
+
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for len(attempts) != 0 {
+		select {
+		case <-ctx.Done():
+			for jid := range attempts {
+				withdraw(jid)
+			}
+			return ctx.Err()
+		case resp := <-cb:
+			if _, ok := attempts[resp.Jid]; !ok {
+				continue
+			}
+			// resubmitted copies of the job are removed so no other worker picks them up.
+			withdraw(resp.Jid)
+
+			rh := results[resp.Jid]
+			rh.Resp = resp
+			data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+			if err == nil {
+				rh.Audio, err = audio.Decode(data)
+			}
+			if err != nil {
+				logrus.WithError(err).WithField("jid", resp.Jid).Warn("bad audio from tts worker")
+				continue
+			}
+			inst.gCtx.Inst().Cache.Put(ctx, rh.Key, data)
+		case now := <-tick.C:
+			for jid, n := range attempts {
+				if now.Before(deadlines[jid]) {
+					continue
+				}
+				if n > cfg.JobRetries {
+					logrus.WithField("jid", jid).Warn("tts job timed out")
+					withdraw(jid)
+					continue
+				}
+				if err := submit(jid); err != nil {
+					logrus.WithError(err).WithField("jid", jid).Error("failed to resubmit tts job")
+				}
+			}
 		}
-		inst.mtx.Unlock()
 	}
+
+	return nil
 }
 
-func (inst *ttsInstance) SendRequest(ctx context.Context, text string, currentVoice parts.Voice, validVoices []parts.Voice, maxVoiceSwaps int) ([]byte, error) {
-	_pts, err := textparser.Process(text, currentVoice, validVoices, maxVoiceSwaps)
+// spamLimits fills the limits a channel does not set with the configured defaults.
+func (inst *ttsInstance) spamLimits(limits datastructures.SpamLimits) spam.Limits {
+	def := inst.gCtx.Config().TextParser.Spam
+	pick := func(v, d int) int {
+		if v == 0 {
+			return d
+		}
+		return v
+	}
+
+	return spam.Limits{
+		Chars:  pick(limits.Chars, def.Chars),
+		Words:  pick(limits.Words, def.Words),
+		Ngrams: pick(limits.Ngrams, def.Ngrams),
+	}
+}
+
+func (inst *ttsInstance) SendRequest(ctx context.Context, req datastructures.TtsRequest) ([]byte, []datastructures.AudioSegment, error) {
+	buf, segments, err := inst.synthesize(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	pts := parts.VoicePartList(_pts)
+	data, err := audio.Encode(buf, outputFormat)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	cb := make(chan Response)
-	results := map[string]*respHelper{}
-	idxMap := map[int]*respHelper{}
+	return data, segments, nil
+}
 
-	tmpPath := path.Join("tmp", uuid.NewString())
-	if err = os.MkdirAll(tmpPath, 0700); err != nil {
-		return nil, err
+// synthesize generates the audio of a request in the output format.
+func (inst *ttsInstance) synthesize(ctx context.Context, req datastructures.TtsRequest) (*audio.Buffer, []datastructures.AudioSegment, error) {
+	// channels can limit the voices available to them.
+	allowed := map[string]bool{}
+	defaultVoice := req.Voice
+	channel, ok := inst.gCtx.Inst().Channels.Get(req.ChannelID)
+	if ok && len(channel.Voices) != 0 {
+		for _, v := range channel.Voices {
+			allowed[v] = true
+		}
+		if !allowed[defaultVoice] {
+			defaultVoice = channel.Voices[0]
+		}
+	}
+
+	validVoices := []parts.Voice{}
+	for _, v := range req.ValidVoices {
+		if voice, ok := textparser.VoicesMap[v]; ok && (len(allowed) == 0 || allowed[v]) {
+			validVoices = append(validVoices, voice)
+		}
+	}
+	for _, v := range append([]string{textparser.BleepSound}, req.Sounds...) {
+		if sound, ok := textparser.SoundsMap[v]; ok {
+			validVoices = append(validVoices, sound)
+		}
 	}
 
-	defer os.RemoveAll(tmpPath)
+	_pts, err := textparser.Process(req.Text, textparser.Options{
+		Voice:         textparser.VoicesMap[defaultVoice],
+		ValidVoices:   validVoices,
+		MaxVoiceSwaps: req.MaxVoiceSwaps,
+		Blacklist:     inst.gCtx.Inst().Blacklist.For(req.ChannelID),
+		Links:         channel.Links,
+		Spam:          inst.spamLimits(channel.Spam.For(req.Trigger.Source)),
+		Markup:        req.Markup,
+		Dictionary:    inst.gCtx.Inst().Pronunciations.For(req.ChannelID),
+
+		MaxUnreadable:    inst.gCtx.Config().TextParser.MaxUnreadable,
+		RejectUnreadable: inst.gCtx.Config().TextParser.RejectUnreadable,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
-	files := []string{}
+	pts := parts.VoicePartList(_pts)
+
+	results := map[string]*respHelper{}
+	idxMap := map[int]*respHelper{}
+	jobs := map[string]job{}
+
+	bufs := []*audio.Buffer{}
 
-	n := 0
 	for voice, ptslist := range pts.Map() {
 		for pt, meta := range ptslist.Unique() {
 			jid, _ := uuid.NewRandom()
@@ -169,9 +447,7 @@ func (inst *ttsInstance) SendRequest(ctx context.Context, text string, currentVo
 				Jid:    jid.String(),
 				Voice:  voice,
 			}
-			inst.mtx.Lock()
-			if voice.Type == parts.VoicePartTypeReader {
-				n++
+			if voice.Type == parts.VoicePartTypeReader && pt.Type != parts.PartTypePause {
 
 				var (
 					CmuDictPath string
@@ -187,76 +463,122 @@ func (inst *ttsInstance) SendRequest(ctx context.Context, text string, currentVo
 					TacoPath = *voice.Entry.TacoPath
 				}
 
-				req, _ := json.MarshalToString(Request{
-					Jid:           jid.String(),
-					Event:         4,
-					ResponseEvent: inst.outputEvent,
-					Mode:          mode,
-					Payload: GenerateChangePayload{
-						Speaker:       voice.Entry.Speaker,
-						GPU:           voice.Entry.GPU,
-						WarmUp:        voice.Entry.WarmUp,
-						GateThreshold: voice.Entry.GateThreshold,
-						Period:        voice.Entry.Period,
-						Start:         voice.Entry.Start,
-						Pace:          voice.Entry.Pace,
-						PitchShift:    voice.Entry.PitchShift,
-						PArpabet:      voice.Entry.PArpabet,
-						Volume:        voice.Entry.Volume,
-						Energy:        voice.Entry.Energy,
-						TacoPath:      TacoPath,
-						FastPath:      FastPath,
-						OnnxPath:      *voice.Entry.OnnxPath,
-						CmuDictPath:   CmuDictPath,
-						Text:          pt.Value,
-					},
-				})
+				payload := GenerateChangePayload{
+					Speaker:       voice.Entry.Speaker,
+					GPU:           voice.Entry.GPU,
+					WarmUp:        voice.Entry.WarmUp,
+					GateThreshold: voice.Entry.GateThreshold,
+					Period:        voice.Entry.Period,
+					Start:         voice.Entry.Start,
+					Pace:          styled(voice.Entry.Pace, pt.Style.Pace),
+					PitchShift:    voice.Entry.PitchShift,
+					PArpabet:      voice.Entry.PArpabet,
+					Volume:        styled(voice.Entry.Volume, pt.Style.Volume),
+					Energy:        voice.Entry.Energy,
+					TacoPath:      TacoPath,
+					FastPath:      FastPath,
+					OnnxPath:      *voice.Entry.OnnxPath,
+					CmuDictPath:   CmuDictPath,
+					Text:          pt.Value,
+				}
 
-				results[jid.String()] = rh
-				inst.cb[jid.String()] = cb
-				if err := inst.gCtx.Inst().Redis.SAdd(ctx, inst.setKey, req); err != nil {
-					return nil, err
+				rh.Key = cacheKey(mode, payload)
+				if data, ok := inst.gCtx.Inst().Cache.Get(ctx, rh.Key); ok {
+					if rh.Audio, err = audio.Decode(data); err != nil {
+						logrus.WithError(err).Warn("bad audio in tts cache")
+					}
+				}
+
+				// cached segments never reach a worker.
+				if rh.Audio == nil {
+					req := datastructures.WorkerRequirements{
+						Voice: payload.Speaker,
+						Mode:  mode,
+						GPU:   payload.GPU,
+					}
+					if !inst.gCtx.Inst().Workers.Available(req) {
+						return nil, nil, fmt.Errorf("%w: %s", ErrNoWorker, voice.Name)
+					}
+
+					data, _ := json.MarshalToString(Request{
+						Jid:            jid.String(),
+						Event:          4,
+						ResponseStream: inst.outputStream,
+						Mode:           mode,
+						Payload:        payload,
+					})
+
+					results[jid.String()] = rh
+					jobs[jid.String()] = job{data: data, req: req}
 				}
 			}
 			for _, v := range meta {
 				idxMap[v.Idx] = rh
 				rh.IdxMap[v.Idx] = v.Space
 			}
-			inst.mtx.Unlock()
 		}
 	}
 
-	for i := 0; i < n; i++ {
-		resp := <-cb
-		results[resp.Jid].Resp = resp
-		sDec, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
-		if err != nil {
-			return nil, err
-		}
-		if err = os.WriteFile(path.Join(tmpPath, fmt.Sprintf("%s.wav", resp.Jid)), sDec, 0666); err != nil {
-			return nil, err
-		}
+	// buffered so process never blocks on a request which stopped waiting, every job is answered at most once.
+	cb := make(chan Response, len(jobs))
+	inst.mtx.Lock()
+	for jid := range jobs {
+		inst.cb[jid] = cb
 	}
+	inst.mtx.Unlock()
 
-	close(cb)
+	defer func() {
+		inst.mtx.Lock()
+		for jid := range jobs {
+			delete(inst.cb, jid)
+		}
+		inst.mtx.Unlock()
+	}()
 
-	used := map[string]bool{
-		"long-pause.wav": true,
+	if err = inst.await(ctx, jobs, cb, results); err != nil {
+		return nil, nil, err
 	}
 
-	longPause := path.Join(tmpPath, "long-pause.wav")
-	if err = os.WriteFile(longPause, soundMap["long-pause.wav"], 0666); err != nil {
-		return nil, err
-	}
+	segments := []datastructures.AudioSegment{}
+	cursor := time.Duration(0)
 
 	for i := 0; i < len(idxMap); i++ {
 		resp := idxMap[i]
+		if pts[i].Type == parts.PartTypePause {
+			silence := audio.Silence(outputFormat, pts[i].Pause)
+			bufs = append(bufs, silence)
+			cursor += silence.Duration()
+			continue
+		}
 		if resp.Voice.Type == parts.VoicePartTypeReader {
-			files = append(files, path.Join(tmpPath, fmt.Sprintf("%s.wav", resp.Jid)))
+			// a segment which could not be generated is left out, its pause still separates its neighbours.
+			if resp.Audio != nil {
+				bufs = append(bufs, resp.Audio)
+
+				duration := resp.Audio.Duration()
+				segments = append(segments, datastructures.AudioSegment{
+					Voice:     resp.Voice.Name,
+					Text:      pts[i].Value,
+					StartTime: cursor,
+					Duration:  duration,
+				})
+				cursor += duration
+			}
+		} else {
+			sound, err := audio.Decode(alerts.Sounds[resp.Voice.Name].Data)
+			if err != nil {
+				return nil, nil, err
+			}
+			bufs = append(bufs, sound)
+
+			duration := sound.Duration()
+			segments = append(segments, datastructures.AudioSegment{
+				Voice:     resp.Voice.Name,
+				StartTime: cursor,
+				Duration:  duration,
+			})
+			cursor += duration
 		}
-		// else {
-		// 	// todo add sound bytes.
-		// }
 		var pause string
 		switch resp.IdxMap[i] {
 		case parts.SpaceTypeLongPause:
@@ -269,57 +591,86 @@ func (inst *ttsInstance) SendRequest(ctx context.Context, text string, currentVo
 			continue
 		}
 		if pause != "" {
-			pth := path.Join(tmpPath, pause)
-			if !used[pause] {
-				if err = os.WriteFile(pth, soundMap[pause], 0666); err != nil {
-					return nil, err
-				}
-				used[pause] = true
-			}
-			files = append(files, pth)
+			bufs = append(bufs, soundMap[pause])
+			cursor += soundMap[pause].Duration()
 		}
 	}
 
-	outPth := path.Join(tmpPath, "output.wav")
+	// cached segments and sound bytes count, only a message without any audio fails.
+	if len(segments) == 0 && len(jobs) != 0 {
+		return nil, nil, ErrNoAudio
+	}
 
 	// add additional long pauses to allow chrome to play the audio correctly.
-	files = append(files, longPause, longPause, longPause, outPth)
+	longPause := soundMap["long-pause.wav"]
+	bufs = append(bufs, longPause, longPause, longPause)
 
-	if err = exec.CommandContext(ctx, "sox", files...).Run(); err != nil {
-		return nil, err
+	return audio.Concat(outputFormat, bufs...), segments, nil
+}
+
+// Generate synthesizes the text of a request and stores the result, requests without text are left alone.
+func (inst *ttsInstance) Generate(ctx context.Context, req datastructures.TtsRequest) error {
+	if req.Text == "" {
+		return nil
+	}
+
+	buf, segments, err := inst.synthesize(ctx, req)
+	if err != nil {
+		return err
+	}
+	data, err := audio.Encode(buf, outputFormat)
+	if err != nil {
+		return err
+	}
+	if err := inst.gCtx.Inst().Redis.Set(ctx, fmt.Sprintf("generated:tts:%s", req.ID.Hex()), utils.B2S(data), time.Hour); err != nil {
+		return err
 	}
 
-	return os.ReadFile(outPth)
+	if err := inst.gCtx.Inst().Mongo.SaveAudio(ctx, datastructures.Audio{
+		ID:        req.ID,
+		ChannelID: req.ChannelID,
+		Duration:  buf.Duration(),
+		Segments:  segments,
+		Trigger:   req.Trigger,
+	}); err != nil {
+		logrus.WithError(err).Error("failed to save audio")
+	}
+
+	return nil
 }
 
-func (inst *ttsInstance) Generate(ctx context.Context, text string, id *primitive.ObjectID, channelID primitive.ObjectID, currentVoice parts.Voice, validVoices []parts.Voice, maxVoiceSwaps int, alert *datastructures.SseEventTtsAlert) error {
-	if text != "" {
-		data, err := inst.SendRequest(ctx, text, currentVoice, validVoices, maxVoiceSwaps)
-		if err != nil {
-			return err
-		}
-		if err := inst.gCtx.Inst().Redis.Set(ctx, fmt.Sprintf("generated:tts:%s", id.Hex()), utils.B2S(data), time.Hour); err != nil {
-			return err
-		}
+// Publish sends a generated request to the overlays of its channel.
+func (inst *ttsInstance) Publish(ctx context.Context, req datastructures.TtsRequest) error {
+	if req.ChannelID.IsZero() {
+		return nil
 	}
 
-	if !channelID.IsZero() {
-		event, err := json.MarshalToString(datastructures.SseEvent{
-			Event: "tts",
-			Payload: datastructures.SseEventTts{
-				WavID: id,
-				Alert: alert,
-			},
-		})
-		if err != nil {
-			return err
-		}
-		if err = inst.gCtx.Inst().Redis.Publish(ctx, fmt.Sprintf("overlay:events:%s", channelID.Hex()), event); err != nil {
-			return err
-		}
+	var wavID *primitive.ObjectID
+	if req.Text != "" {
+		wavID = &req.ID
 	}
 
-	return nil
+	event, err := json.MarshalToString(datastructures.SseEvent{
+		Event: "tts",
+		Payload: datastructures.SseEventTts{
+			WavID: wavID,
+			Alert: req.Alert,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return inst.gCtx.Inst().Redis.Publish(ctx, fmt.Sprintf("overlay:events:%s", req.ChannelID.Hex()), event)
+}
+
+// Discard deletes the generated audio of a request which will never be published.
+func (inst *ttsInstance) Discard(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := inst.gCtx.Inst().Redis.Del(ctx, fmt.Sprintf("generated:tts:%s", id.Hex())); err != nil {
+		return err
+	}
+
+	return inst.gCtx.Inst().Mongo.DeleteAudio(ctx, id)
 }
 
 func (inst *ttsInstance) Skip(ctx context.Context, channelID primitive.ObjectID) error {
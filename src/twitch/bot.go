@@ -11,20 +11,18 @@ import (
 	"time"
 
 	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/events"
 	"github.com/admiralbulldogtv/yappercontroller/src/global"
-	"github.com/admiralbulldogtv/yappercontroller/src/textparser"
-	"github.com/admiralbulldogtv/yappercontroller/src/textparser/parts"
 	"github.com/gempir/go-twitch-irc/v2"
 	"github.com/go-redis/redis/v8"
-	"github.com/hashicorp/go-multierror"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/sirupsen/logrus"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
 type Client interface {
+	events.Source
 	SendMessage(channel string, message string) error
 	SendWhisper(username string, message string) error
 }
@@ -39,7 +37,10 @@ type TokenResp struct {
 }
 
 type twitchClient struct {
-	cl *twitch.Client
+	cl     *twitch.Client
+	events chan events.Event
+	// joined are the logins of the channels the bot joined for their alerts.
+	joined map[string]bool
 }
 
 func NewClient(ctx global.Context) (Client, error) {
@@ -80,102 +81,65 @@ func NewClient(ctx global.Context) (Client, error) {
 		}
 	}
 
-	client := &twitchClient{}
+	client := &twitchClient{
+		events: make(chan events.Event, 100),
+		joined: map[string]bool{},
+	}
 	client.cl = twitch.NewClient(ctx.Config().Twitch.BotUsername, fmt.Sprintf("oauth:%s", token.AccessToken))
 
-	client.cl.Join(ctx.Config().Twitch.BotControlChannel, ctx.Config().Twitch.StreamerChannel, ctx.Config().Twitch.BotUsername)
-	client.cl.OnWhisperMessage(func(message twitch.WhisperMessage) {
-		found := false
-		for _, v := range ctx.Config().Twitch.WhitelistedAccounts {
-			if v == message.User.ID {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return
-		}
+	client.cl.Join(ctx.Config().Twitch.BotControlChannel, ctx.Config().Twitch.BotUsername)
+	client.join(ctx)
 
-		msg := strings.TrimSpace(message.Message)
-		channelID, _ := primitive.ObjectIDFromHex(ctx.Config().TtsChannelID)
-		if strings.HasPrefix(msg, "!say ") {
-			msg = strings.TrimPrefix(msg, "!say ")
-			id := primitive.NewObjectIDFromTimestamp(time.Now())
-			if err := ctx.Inst().TTS.Generate(ctx, msg, &id, channelID, textparser.Voices[0], textparser.Voices, 30, nil); err != nil {
-				if err == textparser.ErrBlacklisted {
-					err = multierror.Append(err, client.SendWhisper(message.User.Name, "failed to generate tts"))
-					logrus.WithError(err).Error("failed to generate tts")
-					return
-				}
-				err = multierror.Append(err, client.SendWhisper(message.User.Name, "failed to generate tts"))
-				logrus.WithError(err).Error("failed to generate tts")
-				return
-			}
-			_ = client.SendWhisper(message.User.Name, "generated tts")
-		} else if msg == "!skip" {
-			err := ctx.Inst().TTS.Skip(ctx, channelID)
-			if err != nil {
-				err = multierror.Append(err, client.SendWhisper(message.User.Name, "failed to skip tts"))
-				logrus.WithError(err).Error("failed to skip tts")
-				return
-			}
-			_ = client.SendWhisper(message.User.Name, "skipped tts")
-		} else if msg == "!reload" {
-			err := ctx.Inst().TTS.Reload(ctx, channelID)
-			if err != nil {
-				_ = client.SendWhisper(message.User.Name, "failed to reload overlay")
-				logrus.WithError(err).Error("failed to reload overlay")
+	joinCh := make(chan struct{}, 1)
+	ctx.Inst().Channels.Watch(joinCh)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
 				return
+			case <-joinCh:
+				client.join(ctx)
 			}
-			_ = client.SendWhisper(message.User.Name, "reloaded overlay")
 		}
-	})
+	}()
 
-	client.cl.OnPrivateMessage(func(message twitch.PrivateMessage) {
-		// ignore non control channel messages.
-		if !strings.EqualFold(message.Channel, ctx.Config().Twitch.BotControlChannel) && !strings.EqualFold(message.Channel, ctx.Config().Twitch.StreamerChannel) && !strings.EqualFold(message.Channel, ctx.Config().Twitch.BotUsername) {
+	client.cl.OnWhisperMessage(func(message twitch.WhisperMessage) {
+		cmd, args := parseCommand(message.Message)
+		target, args := splitTarget(args)
+		channel, ok := resolveChannel(ctx, message.User.ID, target)
+		if !ok {
 			return
 		}
 
-		found := false
-		for _, v := range ctx.Config().Twitch.WhitelistedAccounts {
-			if v == message.User.ID {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return
-		}
+		client.command(ctx, channel, message.User, cmd, args, func(msg string) error {
+			return client.SendWhisper(message.User.Name, msg)
+		})
+	})
 
-		msg := strings.TrimSpace(message.Message)
-		channelID, _ := primitive.ObjectIDFromHex(ctx.Config().TtsChannelID)
-		if strings.HasPrefix(msg, "!say ") {
-			msg = strings.TrimPrefix(msg, "!say ")
-			id := primitive.NewObjectIDFromTimestamp(time.Now())
-			if err := ctx.Inst().TTS.Generate(ctx, msg, &id, channelID, textparser.Voices[0], textparser.Voices, 30, nil); err != nil {
-				err = multierror.Append(err, client.SendMessage(message.Channel, fmt.Sprintf("@%s, failed to generate tts", message.User.DisplayName)))
-				logrus.WithError(err).Error("failed to generate tts")
+	client.cl.OnPrivateMessage(func(message twitch.PrivateMessage) {
+		cmd, args := parseCommand(message.Message)
+		channel, ok := ctx.Inst().Channels.ByLogin(message.Channel)
+		if ok {
+			if !isWhitelisted(ctx, channel, message.User.ID) {
 				return
 			}
-			_ = client.SendMessage(message.Channel, fmt.Sprintf("@%s, generated tts", message.User.DisplayName))
-		} else if msg == "!skip" {
-			err := ctx.Inst().TTS.Skip(ctx, channelID)
-			if err != nil {
-				_ = client.SendMessage(message.Channel, fmt.Sprintf("@%s, failed to skip tts", message.User.DisplayName))
-				logrus.WithError(err).Error("failed to skip tts")
+		} else {
+			// ignore non control channel messages.
+			if !strings.EqualFold(message.Channel, ctx.Config().Twitch.BotControlChannel) && !strings.EqualFold(message.Channel, ctx.Config().Twitch.BotUsername) {
 				return
 			}
-			_ = client.SendMessage(message.Channel, fmt.Sprintf("@%s, skipped tts", message.User.DisplayName))
-		} else if msg == "!reload" {
-			err := ctx.Inst().TTS.Reload(ctx, channelID)
-			if err != nil {
-				_ = client.SendMessage(message.Channel, fmt.Sprintf("@%s, failed to reload overlay", message.User.DisplayName))
-				logrus.WithError(err).Error("failed to reload overlay")
+
+			var target string
+			target, args = splitTarget(args)
+			channel, ok = resolveChannel(ctx, message.User.ID, target)
+			if !ok {
 				return
 			}
-			_ = client.SendMessage(message.Channel, fmt.Sprintf("@%s, reloaded overlay", message.User.DisplayName))
 		}
+
+		client.command(ctx, channel, message.User, cmd, args, func(msg string) error {
+			return client.SendMessage(message.Channel, fmt.Sprintf("@%s, %s", message.User.DisplayName, msg))
+		})
 	})
 
 	mtx := sync.Mutex{}
@@ -205,13 +169,19 @@ func NewClient(ctx global.Context) (Client, error) {
 	}()
 
 	client.cl.OnUserNoticeMessage(func(message twitch.UserNoticeMessage) {
+		channel, ok := ctx.Inst().Channels.ByLogin(message.Channel)
+		if !ok {
+			return
+		}
+
 		mtx.Lock()
 		defer mtx.Unlock()
-		alert := datastructures.AlertHelper{
-			Type: "subscriber",
-		}
-		alertText := ""
-		subText := ""
+		event := events.Event{}
+		event.ChannelID = channel.ID
+		event.Source = datastructures.AudioTriggerSourceGift
+		event.Username = message.User.DisplayName
+		event.Login = message.User.Name
+		event.Tier = events.Tier(message.MsgParams["msg-param-sub-plan"])
 		switch message.MsgID {
 		case "submysterygift": // multi gift subs
 			giftCount, err := strconv.Atoi(message.MsgParams["msg-param-mass-gift-count"])
@@ -241,58 +211,27 @@ func NewClient(ctx global.Context) (Client, error) {
 				logrus.WithError(err).Error("bad read from gift subs")
 				return
 			}
-			subPlan := message.MsgParams["msg-param-sub-plan"]
-			displayName := message.User.DisplayName
-
-			alert.Name = "SubscriberGift"
-			if giftCount >= 5 {
-				alert.Name = "SubscriberGift5"
-			}
-			if giftCount >= 25 {
-				alert.Name = "SubscriberGift25"
-			}
-			if giftCount >= 95 {
-				alert.Name = "SubscriberGift95"
-			}
-			alertText = fmt.Sprintf("~%s gifted ~%d subs", displayName, giftCount)
-			if subPlan != "1000" {
-				alertText = fmt.Sprintf("~%s gifted ~%d tier %s subs", displayName, giftCount, string(subPlan[0]))
-			}
-			subText = fmt.Sprintf("they have gifted %d subs to the channel", senderCount)
+			event.Gifts = giftCount
+			event.SenderCount = senderCount
 		case "subgift":
 			if !bulkGiftMap[message.MsgParams["msg-param-origin-id"]].IsZero() {
 				// has been already handled by the mystrygift event.
 				return
 			}
 			// single giftsub.
-			alert.Name = "SubscriberGift"
-			displayName := message.User.DisplayName
-			recipientDisplayName := message.MsgParams["msg-param-recipient-display-name"]
-			alertText = fmt.Sprintf("~%s gifted a sub to ~%s", displayName, recipientDisplayName)
-			subPlan := message.MsgParams["msg-param-sub-plan"]
-			if subPlan != "1000" {
-				alertText = fmt.Sprintf("~%s gifted a tier %s sub to %s", displayName, string(subPlan[0]), recipientDisplayName)
-			}
+			event.Gifts = 1
+			event.Recipient = message.MsgParams["msg-param-recipient-display-name"]
 
-			var (
-				senderCount int
-				err         error
-			)
 			if v, ok := bulkGiftSingle[message.MsgParams["msg-param-origin-id"]]; ok {
-				senderCount = v
+				event.SenderCount = v
 			} else {
 				if message.MsgParams["msg-param-sender-count"] != "" {
-					senderCount, err = strconv.Atoi(message.MsgParams["msg-param-sender-count"])
+					senderCount, err := strconv.Atoi(message.MsgParams["msg-param-sender-count"])
 					if err != nil {
 						logrus.WithError(err).Error("bad read from gift subs")
 						return
 					}
-				}
-			}
-			if senderCount != 0 {
-				subText = fmt.Sprintf("they have gifted %d subs to the channel", senderCount)
-				if senderCount == 1 {
-					subText = "this is their first gifted sub"
+					event.SenderCount = senderCount
 				}
 			}
 		default:
@@ -300,21 +239,7 @@ func NewClient(ctx global.Context) (Client, error) {
 			return
 		}
 
-		alt := datastructures.SseEventTtsAlert{}
-		image, audio, volume := alert.Parse()
-		alt.Audio = audio
-		alt.Image = image
-		alt.Text = alertText
-		alt.SubText = subText
-		alt.Type = alert.Type
-		alt.Volume = volume
-		go func(alert datastructures.SseEventTtsAlert) {
-			channelId, _ := primitive.ObjectIDFromHex(ctx.Config().TtsChannelID)
-			if err := ctx.Inst().TTS.Generate(ctx, "", nil, channelId, parts.Voice{}, nil, 0, &alert); err != nil {
-				logrus.WithError(err).Error("failed to generate tts")
-			}
-			logrus.Info("generated tts")
-		}(alt)
+		client.events <- event
 	})
 
 	go func() {
@@ -360,6 +285,14 @@ func NewClient(ctx global.Context) (Client, error) {
 	return client, nil
 }
 
+func (c *twitchClient) Name() string {
+	return "twitch"
+}
+
+func (c *twitchClient) Events() <-chan events.Event {
+	return c.events
+}
+
 func (c *twitchClient) SendMessage(channel string, message string) error {
 	c.cl.Say(channel, message)
 	return nil
@@ -403,3 +336,35 @@ func RefreshToken(ctx global.Context, refreshToken string) (TokenResp, error) {
 	err = json.Unmarshal(body, &token)
 	return token, err
 }
+
+// join joins the channels which were added or renamed and departs the channels which are gone,
+// the control channels are always kept.
+func (c *twitchClient) join(ctx global.Context) {
+	logins := map[string]bool{}
+	for _, v := range ctx.Inst().Channels.All() {
+		if v.Twitch.Login != "" {
+			logins[strings.ToLower(v.Twitch.Login)] = true
+		}
+	}
+
+	for login := range c.joined {
+		if logins[login] {
+			continue
+		}
+		delete(c.joined, login)
+		if strings.EqualFold(login, ctx.Config().Twitch.BotControlChannel) || strings.EqualFold(login, ctx.Config().Twitch.BotUsername) {
+			continue
+		}
+		c.cl.Depart(login)
+		logrus.WithField("channel", login).Info("left twitch channel")
+	}
+
+	for login := range logins {
+		if c.joined[login] {
+			continue
+		}
+		c.joined[login] = true
+		c.cl.Join(login)
+		logrus.WithField("channel", login).Info("joined twitch channel")
+	}
+}
@@ -0,0 +1,259 @@
+package twitch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/queue"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/arpabet"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/dictionary"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser/markup"
+	"github.com/admiralbulldogtv/yappercontroller/src/tts"
+	"github.com/gempir/go-twitch-irc/v2"
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// parseCommand splits a message into the command and its arguments.
+func parseCommand(msg string) (string, string) {
+	msg = strings.TrimSpace(msg)
+	idx := strings.IndexByte(msg, ' ')
+	if idx == -1 {
+		return msg, ""
+	}
+
+	return msg[:idx], strings.TrimSpace(msg[idx+1:])
+}
+
+// splitTarget removes an optional leading #channel from the arguments of a command.
+func splitTarget(args string) (string, string) {
+	if !strings.HasPrefix(args, "#") {
+		return "", args
+	}
+
+	target, rest := parseCommand(args)
+	return strings.TrimPrefix(target, "#"), rest
+}
+
+func isWhitelisted(ctx global.Context, channel datastructures.Channel, userID string) bool {
+	for _, v := range ctx.Config().Twitch.WhitelistedAccounts {
+		if v == userID {
+			return true
+		}
+	}
+
+	for _, v := range channel.WhitelistedAccounts {
+		if v == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveChannel finds the channel a command outside of a streamer's chat is meant for.
+// Without a target the first channel the user is whitelisted in is used.
+func resolveChannel(ctx global.Context, userID string, target string) (datastructures.Channel, bool) {
+	for _, v := range ctx.Inst().Channels.All() {
+		if target != "" && !strings.EqualFold(v.Twitch.Login, target) {
+			continue
+		}
+		if isWhitelisted(ctx, v, userID) {
+			return v, true
+		}
+	}
+
+	return datastructures.Channel{}, false
+}
+
+// command runs a command of a whitelisted user, replies are sent through reply.
+func (c *twitchClient) command(ctx global.Context, channel datastructures.Channel, user twitch.User, cmd string, args string, reply func(msg string) error) {
+	switch cmd {
+	case "!say":
+		if args == "" {
+			return
+		}
+		if err := say(ctx, channel.ID, user.Name, args); err != nil {
+			var invalid *arpabet.InvalidError
+			if errors.As(err, &invalid) {
+				_ = reply(invalid.Error())
+				return
+			}
+			if errors.Is(err, tts.ErrNoWorker) {
+				_ = reply(err.Error())
+				return
+			}
+			err = multierror.Append(err, reply("failed to generate tts"))
+			logrus.WithError(err).Error("failed to generate tts")
+			return
+		}
+		_ = reply("generated tts")
+	case "!skip":
+		// !skip <id> drops a queued message, without an id the message which is playing is skipped.
+		if id, err := primitive.ObjectIDFromHex(args); err == nil {
+			if err = ctx.Inst().Queue.Skip(ctx, channel.ID, id); err != nil {
+				err = multierror.Append(err, reply("failed to skip message"))
+				logrus.WithError(err).Error("failed to skip message")
+				return
+			}
+			_ = reply(fmt.Sprintf("skipped %s", id.Hex()))
+			return
+		}
+		if err := ctx.Inst().TTS.Skip(ctx, channel.ID); err != nil {
+			err = multierror.Append(err, reply("failed to skip tts"))
+			logrus.WithError(err).Error("failed to skip tts")
+			return
+		}
+		_ = reply("skipped tts")
+	case "!held":
+		held, err := ctx.Inst().Queue.Held(ctx, channel.ID)
+		if err != nil {
+			err = multierror.Append(err, reply("failed to fetch held messages"))
+			logrus.WithError(err).Error("failed to fetch held messages")
+			return
+		}
+		if len(held) == 0 {
+			_ = reply("no held messages")
+			return
+		}
+		_ = reply(fmt.Sprintf("%d held, next %s from %s: %s", len(held), held[0].ID.Hex(), held[0].Trigger.Username, held[0].Text))
+	case "!approve", "!reject", "!edit":
+		id, text, err := heldTarget(ctx, channel, args)
+		if err != nil {
+			err = multierror.Append(err, reply("no held message"))
+			logrus.WithError(err).Error("failed to find held message")
+			return
+		}
+
+		switch cmd {
+		case "!approve":
+			err = ctx.Inst().Queue.Approve(ctx, channel.ID, id)
+		case "!reject":
+			err = ctx.Inst().Queue.Reject(ctx, channel.ID, id)
+		case "!edit":
+			err = ctx.Inst().Queue.Edit(ctx, channel.ID, id, text)
+		}
+		if err != nil {
+			err = multierror.Append(err, reply(fmt.Sprintf("failed to %s message", cmd[1:])))
+			logrus.WithError(err).Errorf("failed to %s message", cmd[1:])
+			return
+		}
+		_ = reply(fmt.Sprintf("%s %s", strings.TrimSuffix(cmd[1:], "e")+"ed", id.Hex()))
+	case "!pronounce":
+		// !pronounce omegalul oh|meg|ga|lul
+		word, reading := parseCommand(args)
+		if word == "" || reading == "" {
+			_ = reply("usage: !pronounce <word> <pronunciation>")
+			return
+		}
+		_, err := ctx.Inst().Pronunciations.Add(ctx, datastructures.Pronunciation{
+			ChannelID:   channel.ID,
+			Type:        datastructures.PronunciationTypeWord,
+			Pattern:     word,
+			Replacement: reading,
+		})
+		if err != nil {
+			if errors.Is(err, dictionary.ErrInvalidEntry) {
+				_ = reply(err.Error())
+				return
+			}
+			err = multierror.Append(err, reply("failed to add pronunciation"))
+			logrus.WithError(err).Error("failed to add pronunciation")
+			return
+		}
+		_ = reply(fmt.Sprintf("%s is now read as %s", word, reading))
+	case "!unpronounce":
+		word := strings.ToLower(strings.TrimSpace(args))
+		entries, err := ctx.Inst().Pronunciations.Entries(ctx, channel.ID)
+		if err != nil {
+			err = multierror.Append(err, reply("failed to remove pronunciation"))
+			logrus.WithError(err).Error("failed to fetch pronunciations")
+			return
+		}
+		removed := 0
+		for _, e := range entries {
+			if e.Type == datastructures.PronunciationTypeRegex || strings.ToLower(e.Pattern) != word {
+				continue
+			}
+			if err = ctx.Inst().Pronunciations.Remove(ctx, e.ID); err != nil {
+				err = multierror.Append(err, reply("failed to remove pronunciation"))
+				logrus.WithError(err).Error("failed to remove pronunciation")
+				return
+			}
+			removed++
+		}
+		if removed == 0 {
+			_ = reply(fmt.Sprintf("no pronunciation for %s", word))
+			return
+		}
+		_ = reply(fmt.Sprintf("removed the pronunciation of %s", word))
+	case "!reload":
+		if err := ctx.Inst().TTS.Reload(ctx, channel.ID); err != nil {
+			err = multierror.Append(err, reply("failed to reload overlay"))
+			logrus.WithError(err).Error("failed to reload overlay")
+			return
+		}
+		_ = reply("reloaded overlay")
+	}
+}
+
+// heldTarget reads the id of a held message from the arguments, without one the oldest held message is used.
+func heldTarget(ctx global.Context, channel datastructures.Channel, args string) (primitive.ObjectID, string, error) {
+	first, rest := parseCommand(args)
+	if id, err := primitive.ObjectIDFromHex(first); err == nil {
+		return id, rest, nil
+	}
+
+	held, err := ctx.Inst().Queue.Held(ctx, channel.ID)
+	if err != nil {
+		return primitive.NilObjectID, "", err
+	}
+	if len(held) == 0 {
+		return primitive.NilObjectID, "", queue.ErrNotFound
+	}
+
+	return held[0].ID, args, nil
+}
+
+// sayRequest builds the request for a manual !say command, which can use every voice, markup tag and sound.
+func sayRequest(channelID primitive.ObjectID, username string, text string) datastructures.TtsRequest {
+	voices := make([]string, len(textparser.Voices))
+	for i, v := range textparser.Voices {
+		voices[i] = v.Name
+	}
+	sounds := make([]string, len(textparser.Sounds))
+	for i, v := range textparser.Sounds {
+		sounds[i] = v.Name
+	}
+
+	return datastructures.TtsRequest{
+		ID:            primitive.NewObjectIDFromTimestamp(time.Now()),
+		ChannelID:     channelID,
+		Text:          text,
+		Voice:         textparser.Voices[0].Name,
+		ValidVoices:   voices,
+		MaxVoiceSwaps: 30,
+		Markup:        markup.Tags,
+		Sounds:        sounds,
+		Trigger: datastructures.AudioTrigger{
+			Source:   datastructures.AudioTriggerSourceManual,
+			Username: username,
+		},
+	}
+}
+
+// say queues a manual tts and waits until it has been synthesized.
+func say(ctx global.Context, channelID primitive.ObjectID, username string, text string) error {
+	done, err := ctx.Inst().Queue.Enqueue(ctx, sayRequest(channelID, username, text))
+	if err != nil {
+		return err
+	}
+
+	return <-done
+}
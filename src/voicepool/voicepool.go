@@ -0,0 +1,153 @@
+package voicepool
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	"github.com/admiralbulldogtv/yappercontroller/src/textparser"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const DefaultPool = "default"
+
+// Entry is a voice of a pool, voices with a higher weight are picked more often and a weight of 0 disables the voice.
+type Entry struct {
+	Voice  string
+	Weight int
+}
+
+var defaultPools = map[string][]Entry{
+	DefaultPool: {{Voice: "trump", Weight: 1}},
+}
+
+// Roulette picks the default voice of an alert from the pool configured for its event.
+type Roulette struct {
+	noRepeat bool
+	pools    map[string][]Entry
+	events   map[string]string
+
+	mtx  sync.Mutex
+	last map[string]string
+}
+
+func New(ctx global.Context) (*Roulette, error) {
+	cfg := ctx.Config().VoicePools
+
+	r := &Roulette{
+		noRepeat: cfg.NoRepeat,
+		pools:    map[string][]Entry{},
+		events:   map[string]string{},
+		last:     map[string]string{},
+	}
+
+	for name, entries := range cfg.Pools {
+		for _, e := range entries {
+			r.pools[name] = append(r.pools[name], Entry{Voice: e.Voice, Weight: e.Weight})
+		}
+	}
+	if len(r.pools) == 0 {
+		r.pools = defaultPools
+	}
+	for k, v := range cfg.Events {
+		r.events[k] = v
+	}
+
+	if err := Validate(r.pools, r.events); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Validate makes sure every pool only has known voices once and at least one enabled voice,
+// every event points to a pool and there is a default pool for the events without one.
+func Validate(pools map[string][]Entry, events map[string]string) error {
+	for name, entries := range pools {
+		seen := map[string]bool{}
+		enabled := false
+		for _, e := range entries {
+			if _, ok := textparser.VoicesMap[e.Voice]; !ok {
+				return fmt.Errorf("unknown voice %s in voice pool %s", e.Voice, name)
+			}
+			if seen[e.Voice] {
+				return fmt.Errorf("duplicate voice %s in voice pool %s", e.Voice, name)
+			}
+			seen[e.Voice] = true
+			if e.Weight < 0 {
+				return fmt.Errorf("negative weight for voice %s in voice pool %s", e.Voice, name)
+			}
+			if e.Weight > 0 {
+				enabled = true
+			}
+		}
+		if !enabled {
+			return fmt.Errorf("voice pool %s has no voice with a weight", name)
+		}
+	}
+
+	for event, name := range events {
+		if _, ok := pools[name]; !ok {
+			return fmt.Errorf("unknown voice pool %s for event %s", name, event)
+		}
+	}
+	if _, ok := pools[DefaultPool]; !ok {
+		return fmt.Errorf("missing the %s voice pool", DefaultPool)
+	}
+
+	return nil
+}
+
+// Pick returns a random voice of the pool of the event for a channel.
+func (r *Roulette) Pick(channelID primitive.ObjectID, event string) string {
+	name, ok := r.events[event]
+	if !ok {
+		name = DefaultPool
+	}
+	entries := r.pools[name]
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	key := fmt.Sprintf("%s:%s", channelID.Hex(), name)
+	last := r.last[key]
+
+	choices := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Weight > 0 {
+			choices = append(choices, e)
+		}
+	}
+	if r.noRepeat {
+		others := make([]Entry, 0, len(choices))
+		for _, e := range choices {
+			if e.Voice != last {
+				others = append(others, e)
+			}
+		}
+		// a pool with a single enabled voice has to repeat it.
+		if len(others) != 0 {
+			choices = others
+		}
+	}
+
+	total := 0
+	for _, e := range choices {
+		total += e.Weight
+	}
+
+	voice := choices[len(choices)-1].Voice
+	n := rand.Intn(total)
+	for _, e := range choices {
+		if n < e.Weight {
+			voice = e.Voice
+			break
+		}
+		n -= e.Weight
+	}
+
+	r.last[key] = voice
+
+	return voice
+}
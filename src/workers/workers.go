@@ -0,0 +1,158 @@
+package workers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/admiralbulldogtv/yappercontroller/src/datastructures"
+	"github.com/admiralbulldogtv/yappercontroller/src/global"
+	instance "github.com/admiralbulldogtv/yappercontroller/src/instances"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+type workersInstance struct {
+	gCtx        global.Context
+	taskStream  string
+	workerGroup string
+	timeout     time.Duration
+	mtx         sync.Mutex
+	workers     map[string]*datastructures.Worker
+	// groups are the job streams which have the worker group already.
+	groups map[string]bool
+}
+
+// NewInstance keeps track of the workers which sent a heartbeat recently. Every controller receives every
+// heartbeat, so a new controller knows every live worker after one heartbeat interval.
+func NewInstance(ctx global.Context) (instance.Workers, error) {
+	cfg := ctx.Config()
+	inst := &workersInstance{
+		gCtx:        ctx,
+		taskStream:  cfg.Redis.TaskStream,
+		workerGroup: cfg.Redis.WorkerGroup,
+		timeout:     time.Duration(cfg.TTS.WorkerTimeout) * time.Second,
+		workers:     map[string]*datastructures.Worker{},
+		groups:      map[string]bool{},
+	}
+	if inst.taskStream == "" {
+		inst.taskStream = "tts:jobs"
+	}
+	if inst.workerGroup == "" {
+		inst.workerGroup = "workers"
+	}
+	if inst.timeout <= 0 {
+		inst.timeout = 15 * time.Second
+	}
+
+	event := cfg.Redis.HeartbeatEvent
+	if event == "" {
+		event = "tts:heartbeats"
+	}
+
+	ch := make(chan string)
+	ctx.Inst().Redis.Subscribe(ctx, ch, event)
+
+	go func() {
+		tick := time.NewTicker(time.Second)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-ch:
+				inst.heartbeat(msg)
+			case <-tick.C:
+				inst.prune()
+			}
+		}
+	}()
+
+	return inst, nil
+}
+
+func (inst *workersInstance) heartbeat(msg string) {
+	w := &datastructures.Worker{}
+	if err := json.UnmarshalFromString(msg, w); err != nil || w.ID == "" {
+		logrus.WithError(err).Error("bad heartbeat from tts worker")
+		return
+	}
+
+	w.LastSeen = time.Now()
+
+	inst.mtx.Lock()
+	if _, ok := inst.workers[w.ID]; !ok {
+		logrus.WithField("wid", w.ID).Info("tts worker joined")
+	}
+	inst.workers[w.ID] = w
+	inst.mtx.Unlock()
+}
+
+// prune removes the workers which missed their heartbeats.
+func (inst *workersInstance) prune() {
+	inst.mtx.Lock()
+	defer inst.mtx.Unlock()
+
+	for id, w := range inst.workers {
+		if time.Since(w.LastSeen) > inst.timeout {
+			logrus.WithField("wid", id).Warn("tts worker left")
+			delete(inst.workers, id)
+		}
+	}
+}
+
+func (inst *workersInstance) All() []datastructures.Worker {
+	inst.mtx.Lock()
+	defer inst.mtx.Unlock()
+
+	out := make([]datastructures.Worker, 0, len(inst.workers))
+	for _, w := range inst.workers {
+		out = append(out, *w)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+
+	return out
+}
+
+// Available checks if any live worker can generate a job.
+func (inst *workersInstance) Available(req datastructures.WorkerRequirements) bool {
+	inst.mtx.Lock()
+	defer inst.mtx.Unlock()
+
+	for _, w := range inst.workers {
+		if w.Can(req) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Stream returns the job stream of a capability, which every capable worker reads through the worker group.
+// A job read by a worker which dies stays pending in the group, so another worker can claim it.
+func (inst *workersInstance) Stream(ctx context.Context, req datastructures.WorkerRequirements) (string, error) {
+	stream := datastructures.WorkerStream(inst.taskStream, req)
+
+	inst.mtx.Lock()
+	ok := inst.groups[stream]
+	inst.mtx.Unlock()
+	if ok {
+		return stream, nil
+	}
+
+	// jobs added before a worker created the group would otherwise never be read.
+	if err := inst.gCtx.Inst().Redis.XGroupCreate(ctx, stream, inst.workerGroup); err != nil {
+		return "", err
+	}
+
+	inst.mtx.Lock()
+	inst.groups[stream] = true
+	inst.mtx.Unlock()
+
+	return stream, nil
+}